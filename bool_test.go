@@ -0,0 +1,107 @@
+package govalid_test
+
+import (
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateBool(t *testing.T) {
+	t.Run("ok: req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A bool `valid:"req"`
+		}{A: true})
+	})
+	t.Run("fail: req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A bool `valid:"req"`
+		}{}, "field A: required")
+	})
+	t.Run("ok: not req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A bool
+			B bool `valid:"eq:false"`
+		}{})
+	})
+	t.Run("fail: eq true", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A bool `valid:"eq:true"`
+		}{}, "field A: eq true")
+	})
+	t.Run("fail: eq false", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A bool `valid:"eq:false"`
+		}{A: true}, "field A: eq false")
+	})
+	t.Run("fail: eq invalid", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A bool `valid:"eq:yes"`
+		}{}, `field A: tag "eq:yes" at position 4: eq: invalid bool "yes"`)
+	})
+	t.Run("fail: dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []bool `valid:"dive|req"`
+		}{A: []bool{true, false}}, "field A: index 1: required")
+	})
+	t.Run("ok: custom rule skipped for false", func(t *testing.T) {
+		v := govalid.New()
+		v.Rule("never", func(any) error { return govalid.NewValidationError("never") })
+		if err := v.Validate(struct {
+			A bool `valid:"never"`
+		}{}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
+
+func TestValidateInterface(t *testing.T) {
+	type inner struct {
+		B string `valid:"req"`
+	}
+	type stringer interface{ String() string }
+	t.Run("ok: dynamic value", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A any `valid:"req|min:3"`
+		}{A: "abc"})
+	})
+	t.Run("fail: dynamic value", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A any `valid:"min:3"`
+		}{A: 2}, "field A: min 3")
+	})
+	t.Run("fail: nil req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A any `valid:"req"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: nil interface req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A stringer `valid:"req"`
+		}{}, "field A: required")
+	})
+	t.Run("ok: nil not req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A any `valid:"min:3"`
+		}{})
+	})
+	t.Run("fail: zero dynamic value req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A any `valid:"req"`
+		}{A: ""}, "field A: required")
+	})
+	t.Run("fail: dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A any `valid:"dive|email"`
+		}{A: []string{"a@b.co", "b"}}, "field A: index 1: email")
+	})
+	t.Run("fail: slice of any", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []any `valid:"dive|req"`
+		}{A: []any{1, "a", nil}}, "field A: index 2: required")
+	})
+	t.Run("fail: struct", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A any `valid:"req|dive"`
+		}{A: &inner{}}, "field A: field B: required")
+	})
+}
@@ -0,0 +1,36 @@
+package govalid
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// maxCacheEntries is the number of entries a cache holds before it is
+// emptied.
+const maxCacheEntries = 1024
+
+// cache is a concurrent map that is emptied once it holds more than
+// maxCacheEntries entries, so that caching by keys that callers build at
+// run time, like the rules given to Var, can not grow without bound.
+type cache struct {
+	m sync.Map
+	n atomic.Int64
+}
+
+func (c *cache) load(key any) (any, bool) {
+	return c.m.Load(key)
+}
+
+func (c *cache) store(key, value any) {
+	if _, loaded := c.m.Swap(key, value); loaded {
+		return
+	}
+	if c.n.Add(1) > maxCacheEntries {
+		c.clear()
+	}
+}
+
+func (c *cache) clear() {
+	c.m.Clear()
+	c.n.Store(0)
+}
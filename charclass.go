@@ -0,0 +1,96 @@
+package govalid
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// charClass holds the characters a rule accepts, by default and with the
+// "unicode" parameter. Rules without a unicode function, like "ascii", do
+// not take the parameter.
+type charClass struct {
+	ascii   func(c rune) bool
+	unicode func(c rune) bool
+}
+
+// charClasses holds the rules that accept strings made only of certain
+// characters, like "alpha" or "alpha:unicode".
+var charClasses = map[string]charClass{
+	"alpha": {
+		ascii:   isASCIILetter,
+		unicode: func(c rune) bool { return unicode.IsLetter(c) || unicode.IsMark(c) },
+	},
+	"alnum": {
+		ascii:   func(c rune) bool { return isASCIILetter(c) || isASCIIDigit(c) },
+		unicode: func(c rune) bool { return unicode.IsLetter(c) || unicode.IsMark(c) || unicode.IsDigit(c) },
+	},
+	"numeric": {
+		ascii:   isASCIIDigit,
+		unicode: unicode.IsDigit,
+	},
+	"ascii": {
+		ascii: func(c rune) bool { return c < utf8.RuneSelf },
+	},
+	"printascii": {
+		ascii:   func(c rune) bool { return ' ' <= c && c <= '~' },
+		unicode: unicode.IsPrint,
+	},
+	"lower": {
+		ascii:   func(c rune) bool { return c < utf8.RuneSelf && (c < 'A' || c > 'Z') },
+		unicode: func(c rune) bool { return !unicode.IsUpper(c) && !unicode.IsTitle(c) },
+	},
+	"upper": {
+		ascii:   func(c rune) bool { return c < utf8.RuneSelf && (c < 'a' || c > 'z') },
+		unicode: func(c rune) bool { return !unicode.IsLower(c) && !unicode.IsTitle(c) },
+	},
+	"nospace": {
+		ascii:   func(c rune) bool { return c < utf8.RuneSelf && !strings.ContainsRune(" \t\n\v\f\r", c) },
+		unicode: func(c rune) bool { return !unicode.IsSpace(c) },
+	},
+	"slug": {
+		ascii: func(c rune) bool { return 'a' <= c && c <= 'z' || isASCIIDigit(c) || c == '-' },
+		unicode: func(c rune) bool {
+			return unicode.IsLetter(c) && !unicode.IsUpper(c) && !unicode.IsTitle(c) || unicode.IsMark(c) || unicode.IsDigit(c) || c == '-'
+		},
+	},
+}
+
+func init() {
+	for name := range charClasses {
+		stringRules[name] = isCharClass
+		builtinRules[name] = true
+	}
+}
+
+// isCharClass reports whether every character of s is in the class of the
+// rule. With the "unicode" parameter s must also be valid UTF-8. A slug
+// must also not be empty, start or end with a hyphen or contain two in a
+// row.
+func isCharClass(s string, r *rule) bool {
+	class := charClasses[r.name]
+	in := class.ascii
+	if r.param == "unicode" {
+		if !utf8.ValidString(s) {
+			return false
+		}
+		in = class.unicode
+	}
+	for _, c := range s {
+		if !in(c) {
+			return false
+		}
+	}
+	if r.name == "slug" {
+		return s != "" && s[0] != '-' && s[len(s)-1] != '-' && !strings.Contains(s, "--")
+	}
+	return true
+}
+
+func isASCIILetter(c rune) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isASCIIDigit(c rune) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,51 @@
+package govalid_test
+
+import (
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateCharClasses(t *testing.T) {
+	rulesMustMatch(t, []ruleCase{
+		{rules: "alpha", valid: []string{"Gopher", "abc"}, fail: []string{"gopher1", "go pher", "café"}},
+		{rules: "alpha:unicode", valid: []string{"café", "café", "Γειά", "日本"}, fail: []string{"café1", "go pher", "\xff"}},
+		{rules: "alnum", valid: []string{"Gopher1", "123"}, fail: []string{"gopher_1", "naïve1"}},
+		{rules: "alnum:unicode", valid: []string{"naïve1", "٣abc"}, fail: []string{"naïve 1", "a-b"}},
+		{rules: "numeric", valid: []string{"0123"}, fail: []string{"-1", "1.5", "٣"}},
+		{rules: "numeric:unicode", valid: []string{"0123", "٣٤"}, fail: []string{"1.5", "½"}},
+		{rules: "ascii", valid: []string{"Go!\t\n~"}, fail: []string{"café", "\xff"}},
+		{rules: "printascii", valid: []string{"Go! ~"}, fail: []string{"Go\t", "café", "\x7f"}},
+		{rules: "printascii:unicode", valid: []string{"café !"}, fail: []string{"café\n", "​"}},
+		{rules: "lower", valid: []string{"gopher 1!"}, fail: []string{"Gopher", "éa", "Éa"}},
+		{rules: "lower:unicode", valid: []string{"école 1!"}, fail: []string{"École", "ǅ"}},
+		{rules: "upper", valid: []string{"GOPHER 1!"}, fail: []string{"GOPHEr", "ÉA", "éA"}},
+		{rules: "upper:unicode", valid: []string{"ÉCOLE 1!"}, fail: []string{"ÉCOLe", "ǅ"}},
+		{rules: "nospace", valid: []string{"go-pher"}, fail: []string{"go pher", "go\tpher", "go\npher", "go pher"}},
+		{rules: "nospace:unicode", valid: []string{"go-pher"}, fail: []string{"go pher", "go pher", "go pher"}},
+		{rules: "slug", valid: []string{"hello-world-2", "a"}, fail: []string{"Hello-world", "hello_world", "-hello", "hello-", "hello--world", "café"}},
+		{rules: "slug:unicode", valid: []string{"café-au-lait", "日本-2"}, fail: []string{"Café", "café--lait", "café lait"}},
+	})
+	t.Run("fail: dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []string `valid:"dive|req|slug"`
+		}{A: []string{"a-b", "A-B"}}, "field A: index 1: slug")
+	})
+	t.Run("fail: invalid variant", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"alpha:utf8"`
+		}{}, `field A: tag "alpha:utf8" at position 7: alpha: invalid variant "utf8"`)
+	})
+	t.Run("fail: ascii unicode", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"ascii:unicode"`
+		}{}, `ascii: invalid variant "unicode"`)
+	})
+	t.Run("ok: custom rule replaces built-in", func(t *testing.T) {
+		v := govalid.New()
+		v.Rule("alpha", func(any) error { return nil })
+		if err := v.Var("123", "alpha"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
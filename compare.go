@@ -0,0 +1,19 @@
+package govalid
+
+// compareRules maps the rules comparing a value with their parameter to
+// the result of the comparison they accept. Numbers are compared by
+// value, strings lexically and slices and maps by length.
+var compareRules = map[string]func(c int) bool{
+	"eq":  func(c int) bool { return c == 0 },
+	"ne":  func(c int) bool { return c != 0 },
+	"gt":  func(c int) bool { return c > 0 },
+	"gte": func(c int) bool { return c >= 0 },
+	"lt":  func(c int) bool { return c < 0 },
+	"lte": func(c int) bool { return c <= 0 },
+}
+
+func init() {
+	for name := range compareRules {
+		builtinRules[name] = true
+	}
+}
@@ -0,0 +1,105 @@
+package govalid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		msg  string
+	}{
+		{name: "float gt", val: struct {
+			A float64 `valid:"gt:0"`
+		}{A: 0.01}},
+		{name: "float not gt", val: struct {
+			A float64 `valid:"gt:0"`
+		}{A: -0.01}, msg: "field A: gt 0.000000"},
+		{name: "float zero gt with always", val: struct {
+			A float32 `valid:"always|gt:0"`
+		}{}, msg: "field A: gt 0.000000"},
+		{name: "float lt", val: struct {
+			A float64 `valid:"lt:1.5"`
+		}{A: 1.5}, msg: "field A: lt 1.500000"},
+		{name: "int gte", val: struct {
+			A int `valid:"gte:3"`
+		}{A: 3}},
+		{name: "int not gte", val: struct {
+			A int8 `valid:"gte:3"`
+		}{A: 2}, msg: "field A: gte 3"},
+		{name: "int lte", val: struct {
+			A int64 `valid:"lte:-1"`
+		}{A: 1}, msg: "field A: lte -1"},
+		{name: "int ne", val: struct {
+			A int `valid:"ne:7"`
+		}{A: 7}, msg: "field A: ne 7"},
+		{name: "int eq", val: struct {
+			A int16 `valid:"eq:7"`
+		}{A: 7}},
+		{name: "int not int", val: struct {
+			A int `valid:"eq:1.5"`
+		}{A: 1}, msg: `field A: tag "eq:1.5" at position 4: eq: invalid integer "1.5"`},
+		{name: "uint gt", val: struct {
+			A uint8 `valid:"gt:9"`
+		}{A: 9}, msg: "field A: gt 9"},
+		{name: "uint lt", val: struct {
+			A uint64 `valid:"lt:10"`
+		}{A: 9}},
+		{name: "uint negative", val: struct {
+			A uint `valid:"gt:-1"`
+		}{A: 1}, msg: `field A: tag "gt:-1" at position 4: gt: invalid unsigned integer "-1"`},
+		{name: "duration gt", val: struct {
+			A time.Duration `valid:"gt:1s"`
+		}{A: time.Second}, msg: "field A: gt 1s"},
+		{name: "string eq", val: struct {
+			A string `valid:"eq:yes"`
+		}{A: "no"}, msg: "field A: eq yes"},
+		{name: "string ne", val: struct {
+			A string `valid:"ne:admin"`
+		}{A: "admin"}, msg: "field A: ne admin"},
+		{name: "string gte", val: struct {
+			A string `valid:"gte:b|lt:c"`
+		}{A: "bz"}},
+		{name: "string not lt", val: struct {
+			A string `valid:"gte:b|lt:c"`
+		}{A: "c"}, msg: "field A: lt c"},
+		{name: "slice gt", val: struct {
+			A []int `valid:"gt:1"`
+		}{A: []int{1}}, msg: "field A: gt 1"},
+		{name: "slice eq", val: struct {
+			A []int `valid:"eq:2"`
+		}{A: []int{1, 2}}},
+		{name: "map lte", val: struct {
+			A map[string]int `valid:"lte:1"`
+		}{A: map[string]int{"a": 1, "b": 2}}, msg: "field A: lte 1"},
+		{name: "dive", val: struct {
+			A []float64 `valid:"dive|gt:0"`
+		}{A: []float64{1, -1}}, msg: "field A: index 1: gt 0.000000"},
+		{name: "bool ne", val: struct {
+			A bool `valid:"ne:true"`
+		}{A: true}, msg: "field A: ne true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := govalid.Validate(tt.val)
+			if tt.msg == "" {
+				if err != nil {
+					t.Fatalf("expected nil err; got %s", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.msg {
+				t.Fatalf("expected err %s; got %v", tt.msg, err)
+			}
+		})
+	}
+	t.Run("ok: var", func(t *testing.T) {
+		if err := govalid.Var(0.5, "gt:0|lte:1"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
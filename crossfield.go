@@ -0,0 +1,262 @@
+package govalid
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// crossFieldRules maps the rules comparing a field with another field of
+// the same struct to the result of the comparison they accept.
+var crossFieldRules = map[string]func(c int) bool{
+	"eqfield":  func(c int) bool { return c == 0 },
+	"nefield":  func(c int) bool { return c != 0 },
+	"gtfield":  func(c int) bool { return c > 0 },
+	"gtefield": func(c int) bool { return c >= 0 },
+	"ltfield":  func(c int) bool { return c < 0 },
+	"ltefield": func(c int) bool { return c <= 0 },
+}
+
+// conditionalRules holds the rules that make a field required depending
+// on other fields of the same struct.
+var conditionalRules = map[string]bool{
+	"required_if":      true,
+	"required_unless":  true,
+	"required_with":    true,
+	"required_without": true,
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// fieldRef is a resolved reference to another field of a struct.
+type fieldRef struct {
+	// index holds the index of each field along the path.
+	index [][]int
+	typ   reflect.Type
+}
+
+// resolveField finds the field named by path, like "Start" or
+// "Period.Start", in struct type t.
+func resolveField(t reflect.Type, path string) (fieldRef, error) {
+	var ref fieldRef
+	for name := range strings.SplitSeq(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return ref, fmt.Errorf("no field %s", path)
+		}
+		sf, ok := t.FieldByName(name)
+		if !ok || !sf.IsExported() {
+			return ref, fmt.Errorf("no field %s", path)
+		}
+		ref.index = append(ref.index, sf.Index)
+		t = sf.Type
+	}
+	ref.typ = t
+	return ref, nil
+}
+
+// resolveFields resolves the fields a cross-field or conditional rule in
+// tag refers to in struct type t. The field of type ft a cross-field rule
+// applies to must be comparable with the other field.
+func resolveFields(t, ft reflect.Type, tag string, r *rule) error {
+	var paths []string
+	switch r.name {
+	case "required_if", "required_unless":
+		args, err := r.tok.splitBy(tag, ' ')
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 || len(args)%2 != 0 {
+			return &TagError{Tag: tag, Pos: r.tok.paramPos + 1, Err: fmt.Errorf("%s: expected pairs of field and value", r.name)}
+		}
+		for i := 0; i < len(args); i += 2 {
+			paths = append(paths, args[i])
+			r.values = append(r.values, args[i+1])
+		}
+	case "required_with", "required_without":
+		var err error
+		if paths, err = r.tok.split(tag); err != nil {
+			return err
+		}
+	default:
+		paths = []string{r.param}
+	}
+	for _, path := range paths {
+		ref, err := resolveField(t, path)
+		if err != nil {
+			return &TagError{Tag: tag, Pos: r.tok.paramPos + 1, Err: fmt.Errorf("%s: %w", r.name, err)}
+		}
+		if crossFieldRules[r.name] != nil && !comparableTypes(ft, ref.typ) {
+			return &TagError{Tag: tag, Pos: r.tok.paramPos + 1, Err: fmt.Errorf("%s: can not compare %s with %s", r.name, ft, ref.typ)}
+		}
+		r.fields = append(r.fields, ref)
+	}
+	return nil
+}
+
+// otherField returns the value of the field ref refers to. A nil pointer
+// along the way gives the zero value of the field.
+func otherField(parent reflect.Value, ref fieldRef) reflect.Value {
+	v := parent
+	for _, index := range ref.index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Zero(ref.typ)
+			}
+			v = v.Elem()
+		}
+		var err error
+		if v, err = v.FieldByIndexErr(index); err != nil {
+			return reflect.Zero(ref.typ)
+		}
+	}
+	return v
+}
+
+// requiredBy returns the first of the conditional rules of a field of the
+// struct parent that makes the field required, or nil.
+func requiredBy(parent reflect.Value, rules []*rule) *rule {
+	for _, rule := range rules {
+		switch rule.name {
+		case "required_if":
+			if fieldsEqual(parent, rule) {
+				return rule
+			}
+		case "required_unless":
+			if !fieldsEqual(parent, rule) {
+				return rule
+			}
+		case "required_with":
+			for _, ref := range rule.fields {
+				if !otherField(parent, ref).IsZero() {
+					return rule
+				}
+			}
+		case "required_without":
+			for _, ref := range rule.fields {
+				if otherField(parent, ref).IsZero() {
+					return rule
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fieldsEqual tells whether every field a required_if or required_unless
+// rule refers to has the value given in the rule.
+func fieldsEqual(parent reflect.Value, r *rule) bool {
+	for i, ref := range r.fields {
+		v := otherField(parent, ref)
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return false
+			}
+			v = v.Elem()
+		}
+		if fmt.Sprint(v.Interface()) != r.values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// comparableTypes tells whether compareValues can compare values of types
+// a and b.
+func comparableTypes(a, b reflect.Type) bool {
+	for a.Kind() == reflect.Pointer {
+		a = a.Elem()
+	}
+	for b.Kind() == reflect.Pointer {
+		b = b.Elem()
+	}
+	if a == timeType || b == timeType {
+		return a == b
+	}
+	switch {
+	case isIntKind(a.Kind()) || isUintKind(a.Kind()):
+		return isIntKind(b.Kind()) || isUintKind(b.Kind())
+	case a.Kind() == reflect.Float32 || a.Kind() == reflect.Float64:
+		return b.Kind() == reflect.Float32 || b.Kind() == reflect.Float64
+	case a.Kind() == reflect.String || a.Kind() == reflect.Bool:
+		return a.Kind() == b.Kind()
+	}
+	return false
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return reflect.Int <= k && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return reflect.Uint <= k && k <= reflect.Uintptr
+}
+
+// compareValues compares two strings, numbers, bools or times.
+func compareValues(a, b reflect.Value) (int, error) {
+	for a.Kind() == reflect.Pointer {
+		if a.IsNil() {
+			a = reflect.Zero(a.Type().Elem())
+		} else {
+			a = a.Elem()
+		}
+	}
+	for b.Kind() == reflect.Pointer {
+		if b.IsNil() {
+			b = reflect.Zero(b.Type().Elem())
+		} else {
+			b = b.Elem()
+		}
+	}
+	if a.Type() == timeType && b.Type() == timeType {
+		return a.Interface().(time.Time).Compare(b.Interface().(time.Time)), nil
+	}
+	switch {
+	case a.CanInt() && b.CanInt():
+		return cmp.Compare(a.Int(), b.Int()), nil
+	case a.CanUint() && b.CanUint():
+		return cmp.Compare(a.Uint(), b.Uint()), nil
+	case a.CanInt() && b.CanUint():
+		if a.Int() < 0 {
+			return -1, nil
+		}
+		return cmp.Compare(uint64(a.Int()), b.Uint()), nil
+	case a.CanUint() && b.CanInt():
+		if b.Int() < 0 {
+			return 1, nil
+		}
+		return cmp.Compare(a.Uint(), uint64(b.Int())), nil
+	case a.CanFloat() && b.CanFloat():
+		return cmp.Compare(a.Float(), b.Float()), nil
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return strings.Compare(a.String(), b.String()), nil
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		if a.Bool() == b.Bool() {
+			return 0, nil
+		}
+		if b.Bool() {
+			return -1, nil
+		}
+		return 1, nil
+	}
+	return 0, fmt.Errorf("can not compare %s with %s", a.Type(), b.Type())
+}
+
+// validateCrossField checks the cross-field rules of field fv of the
+// struct parent.
+func validateCrossField(parent, fv reflect.Value, rules []*rule) error {
+	for _, rule := range rules {
+		c, err := compareValues(fv, otherField(parent, rule.fields[0]))
+		if err != nil {
+			return fmt.Errorf("%s: %w", rule.name, err)
+		}
+		if !crossFieldRules[rule.name](c) {
+			return newRuleError(rule.name, rule.param, fv.Interface(), fmt.Sprintf("%s %s", rule.name, rule.param))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package govalid_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateCrossField(t *testing.T) {
+	type Signup struct {
+		Password        string `valid:"req"`
+		ConfirmPassword string `valid:"req|eqfield:Password"`
+	}
+	t.Run("fail: eqfield", func(t *testing.T) {
+		validationErrMustInclude(t, Signup{Password: "a", ConfirmPassword: "b"}, "field ConfirmPassword: eqfield Password")
+	})
+	t.Run("ok: eqfield", func(t *testing.T) {
+		errMustBeNil(t, Signup{Password: "a", ConfirmPassword: "a"})
+	})
+	t.Run("fail: nefield", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A int `valid:"nefield:B"`
+			B int
+		}{A: 1, B: 1}, "nefield B")
+	})
+	t.Run("ok: nefield not req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A int `valid:"nefield:B"`
+			B int
+		}{})
+	})
+	type Period struct {
+		Start time.Time
+		End   time.Time `valid:"gtfield:Start"`
+	}
+	now := time.Now()
+	t.Run("fail: gtfield time", func(t *testing.T) {
+		validationErrMustInclude(t, Period{Start: now, End: now}, "gtfield Start")
+	})
+	t.Run("ok: gtfield time", func(t *testing.T) {
+		errMustBeNil(t, Period{Start: now, End: now.Add(time.Hour)})
+	})
+	t.Run("fail: gtefield float", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			Min float64
+			Max float64 `valid:"gtefield:Min"`
+		}{Min: 2, Max: 1.5}, "gtefield Min")
+	})
+	t.Run("ok: gtefield uint and int", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			Min int
+			Max uint8 `valid:"gtefield:Min"`
+		}{Min: -1, Max: 1})
+	})
+	t.Run("fail: ltfield string", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"ltfield:B"`
+			B string
+		}{A: "b", B: "a"}, "ltfield B")
+	})
+	t.Run("ok: ltefield", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A int `valid:"ltefield:B"`
+			B int
+		}{A: 2, B: 2})
+	})
+	type Booking struct {
+		Period   *Period
+		Checkout time.Time `valid:"ltefield:Period.End"`
+	}
+	t.Run("fail: nested", func(t *testing.T) {
+		validationErrMustInclude(t, Booking{Period: &Period{End: now}, Checkout: now.Add(time.Hour)}, "ltefield Period.End")
+	})
+	t.Run("ok: nested", func(t *testing.T) {
+		errMustBeNil(t, Booking{Period: &Period{End: now}, Checkout: now})
+	})
+	t.Run("fail: nested nil", func(t *testing.T) {
+		validationErrMustInclude(t, Booking{Checkout: now}, "ltefield Period.End")
+	})
+	t.Run("illegal: unknown field", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A int `valid:"eqfield:C"`
+		}{A: 1}, "field A", "no field C")
+	})
+	t.Run("illegal: mismatched types", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A int `valid:"eqfield:B"`
+			B string
+		}{A: 1}, "can not compare")
+	})
+	t.Run("illegal: mismatched types zero", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A int `valid:"gtfield:B"`
+			B float64
+		}{}, `field A: tag "gtfield:B" at position 9: gtfield: can not compare int with float64`)
+	})
+	t.Run("illegal: mismatched types check", func(t *testing.T) {
+		err := govalid.Check(reflect.TypeFor[struct {
+			A time.Time `valid:"gtfield:B"`
+			B *int64
+		}]())
+		if err == nil || err.Error() != `field A: tag "gtfield:B" at position 9: gtfield: can not compare time.Time with *int64` {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("illegal: after dive", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A []int `valid:"dive|eqfield:B"`
+			B int
+		}{}, `field A: tag "dive|eqfield:B" at position 6: eqfield: can not be used after dive or within keys`)
+	})
+	t.Run("illegal: within keys", func(t *testing.T) {
+		err := govalid.Check(reflect.TypeFor[struct {
+			A map[string]int `valid:"keys|required_with:B|endkeys"`
+			B string
+		}]())
+		if err == nil || err.Error() != `field A: tag "keys|required_with:B|endkeys" at position 6: required_with: can not be used after dive or within keys` {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+}
+
+func TestValidateConditionalRequired(t *testing.T) {
+	type Order struct {
+		DeliveryMethod  string
+		ShippingAddress string `valid:"required_if:DeliveryMethod ship|min:5"`
+		Email           string
+		Phone           string `valid:"required_without:Email"`
+		Gift            *bool
+		GiftNote        string `valid:"required_with:Gift"`
+		Country         string
+		State           string `valid:"required_unless:Country US"`
+	}
+	valid := Order{Email: "a@b.c", Country: "US"}
+	t.Run("fail: required_if", func(t *testing.T) {
+		o := valid
+		o.DeliveryMethod = "ship"
+		validationErrMustInclude(t, o, "field ShippingAddress: required")
+	})
+	t.Run("ok: required_if", func(t *testing.T) {
+		o := valid
+		o.DeliveryMethod = "pickup"
+		errMustBeNil(t, o)
+	})
+	t.Run("fail: required_if remaining rules", func(t *testing.T) {
+		o := valid
+		o.DeliveryMethod = "ship"
+		o.ShippingAddress = "a"
+		validationErrMustInclude(t, o, "field ShippingAddress: min 5")
+	})
+	t.Run("fail: required_without", func(t *testing.T) {
+		o := valid
+		o.Email = ""
+		validationErrMustInclude(t, o, "field Phone: required")
+	})
+	t.Run("ok: required_without", func(t *testing.T) {
+		o := valid
+		o.Email = ""
+		o.Phone = "555"
+		errMustBeNil(t, o)
+	})
+	t.Run("fail: required_with", func(t *testing.T) {
+		o := valid
+		o.Gift = ptr(false)
+		validationErrMustInclude(t, o, "field GiftNote: required")
+	})
+	t.Run("fail: required_unless", func(t *testing.T) {
+		o := valid
+		o.Country = "CA"
+		validationErrMustInclude(t, o, "field State: required")
+	})
+	t.Run("illegal: odd pairs", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"required_if:B"`
+			B string
+		}{}, "required_if", "pairs")
+	})
+	t.Run("illegal: unknown field", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"required_with:B,C"`
+			B string
+		}{}, "required_with", "no field C")
+	})
+}
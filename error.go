@@ -2,21 +2,130 @@ package govalid
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 )
 
 type ValidationError interface {
-	// govalidError could be replaced in future with
-	// helpful functions, like Field() string
+	// Path returns the location of the value that failed, outermost
+	// segment first. It is empty when the validated value itself failed.
+	Path() []PathSegment
+
+	// Field returns the path in a form like "Items[3].Name".
+	Field() string
+
+	// Rule returns the name of the rule that failed, like "min".
+	Rule() string
+
+	// Param returns the parameter of the rule that failed, like "3" for
+	// "min:3".
+	Param() string
+
+	// Value returns the value that failed the rule.
+	Value() any
+
 	govalidError()
 	Error() string
 }
 
+// SegmentKind tells what a PathSegment refers to.
+type SegmentKind int
+
+const (
+	FieldSegment SegmentKind = iota
+	IndexSegment
+	KeySegment
+)
+
+// PathSegment is one step in the location of a failed value: a struct
+// field, a slice or array index, or a map key.
+type PathSegment struct {
+	Kind  SegmentKind
+	Field string
+	Index int
+	Key   any
+}
+
+func (s PathSegment) String() string {
+	switch s.Kind {
+	case IndexSegment:
+		return fmt.Sprintf("index %d", s.Index)
+	case KeySegment:
+		return fmt.Sprintf("key %v", s.Key)
+	}
+	return fmt.Sprintf("field %s", s.Field)
+}
+
+func fieldSegment(name string) PathSegment {
+	return PathSegment{Kind: FieldSegment, Field: name}
+}
+
+func indexSegment(i int) PathSegment {
+	return PathSegment{Kind: IndexSegment, Index: i}
+}
+
 type validationError struct {
-	msg string
+	path  []PathSegment
+	rule  string
+	param string
+	value any
+	msg   string
+
+	// origin is the error created by NewValidationError. It is kept
+	// when the error is wrapped so errors.Is still matches it.
+	origin *validationError
 }
 
 func (e *validationError) Error() string {
-	return e.msg
+	if len(e.path) == 0 {
+		return e.msg
+	}
+	var sb strings.Builder
+	for _, seg := range e.path {
+		sb.WriteString(seg.String())
+		sb.WriteString(": ")
+	}
+	sb.WriteString(e.msg)
+	return sb.String()
+}
+
+func (e *validationError) Path() []PathSegment {
+	return slices.Clone(e.path)
+}
+
+func (e *validationError) Field() string {
+	var sb strings.Builder
+	for _, seg := range e.path {
+		switch seg.Kind {
+		case IndexSegment:
+			fmt.Fprintf(&sb, "[%d]", seg.Index)
+		case KeySegment:
+			fmt.Fprintf(&sb, "[%v]", seg.Key)
+		default:
+			if sb.Len() > 0 {
+				sb.WriteByte('.')
+			}
+			sb.WriteString(seg.Field)
+		}
+	}
+	return sb.String()
+}
+
+func (e *validationError) Rule() string {
+	return e.rule
+}
+
+func (e *validationError) Param() string {
+	return e.param
+}
+
+func (e *validationError) Value() any {
+	return e.value
+}
+
+func (e *validationError) Is(target error) bool {
+	t, ok := target.(*validationError)
+	return ok && t.origin == e.origin
 }
 
 func (e *validationError) govalidError() {
@@ -24,16 +133,121 @@ func (e *validationError) govalidError() {
 }
 
 func NewValidationError(msg string) ValidationError {
-	return &validationError{msg: msg}
+	e := &validationError{msg: msg}
+	e.origin = e
+	return e
 }
 
-func wrap(prefix string, err error) error {
+// NewFieldError returns a validation error for the field at path, like
+// "End" or "Items[3].Name", relative to the struct whose ValidateStruct
+// method returns it.
+func NewFieldError(path string, msg string) ValidationError {
+	segs, err := parsePath(path)
+	if err != nil {
+		segs = []PathSegment{fieldSegment(path)}
+	}
+	e := &validationError{path: segs, msg: msg}
+	e.origin = e
+	return e
+}
+
+// newRuleError returns the error for a value that failed a built-in rule.
+func newRuleError(rule, param string, value any, msg string) *validationError {
+	e := &validationError{rule: rule, param: param, value: value, msg: msg}
+	e.origin = e
+	return e
+}
+
+// annotate fills in the rule and value of a validation error returned by a
+// custom rule.
+func annotate(err error, rule, param string, value any) error {
 	verr, ok := err.(*validationError)
-	if ok {
-		return NewValidationError(fmt.Sprintf("%s: %s", prefix, verr))
+	if !ok || verr.rule != "" {
+		return err
+	}
+	e := *verr
+	e.rule, e.param = rule, param
+	e.value = value
+	return &e
+}
+
+// ValidationErrors is returned by ValidateAll. It holds every validation
+// failure in the order the fields were visited. Path, Field, Rule, Param
+// and Value report the first failure.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e ValidationErrors) Path() []PathSegment {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0].Path()
+}
+
+func (e ValidationErrors) Field() string {
+	if len(e) == 0 {
+		return ""
+	}
+	return e[0].Field()
+}
+
+func (e ValidationErrors) Rule() string {
+	if len(e) == 0 {
+		return ""
+	}
+	return e[0].Rule()
+}
+
+func (e ValidationErrors) Param() string {
+	if len(e) == 0 {
+		return ""
+	}
+	return e[0].Param()
+}
+
+func (e ValidationErrors) Value() any {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0].Value()
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e ValidationErrors) govalidError() {
+	panic("do not call this")
+}
+
+func wrap(seg PathSegment, err error) error {
+	switch verr := err.(type) {
+	case *validationError:
+		e := *verr
+		e.path = make([]PathSegment, 0, len(verr.path)+1)
+		e.path = append(append(e.path, seg), verr.path...)
+		return &e
+	case ValidationErrors:
+		errs := make(ValidationErrors, len(verr))
+		for i, e := range verr {
+			errs[i] = wrap(seg, e).(ValidationError)
+		}
+		return errs
 	}
-	return fmt.Errorf("%s: %w", prefix, err)
+	return fmt.Errorf("%s: %w", seg, err)
 }
 
 var _ error = (*validationError)(nil)
 var _ ValidationError = (*validationError)(nil)
+var _ ValidationError = ValidationErrors(nil)
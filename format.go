@@ -0,0 +1,116 @@
+package govalid
+
+import (
+	"net/mail"
+	"net/url"
+	"strings"
+)
+
+// stringRules holds the built-in rules that check the format of a string.
+// They are skipped for empty strings unless the field is required, like
+// every other rule.
+var stringRules = map[string]func(s string, r *rule) bool{
+	"email":    isEmail,
+	"url":      isURL,
+	"uri":      isURI,
+	"hostname": isHostname,
+	"fqdn":     isFQDN,
+}
+
+func init() {
+	for name := range stringRules {
+		builtinRules[name] = true
+	}
+}
+
+// isEmail reports whether s is a bare address as specified by RFC 5322,
+// like "gopher@example.com", without a display name or angle brackets.
+func isEmail(s string, _ *rule) bool {
+	if len(s) > 254 {
+		return false
+	}
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Name == "" && addr.Address == s
+}
+
+// isURL reports whether s is an absolute URL with a host, like
+// "https://example.com/a". The rule may list the allowed schemes, like
+// "url:https,http".
+func isURL(s string, r *rule) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+	return allowedScheme(u.Scheme, r.values)
+}
+
+// isURI reports whether s is an absolute URI as specified by RFC 3986,
+// like "mailto:gopher@example.com" or "urn:isbn:0451450523". The rule may
+// list the allowed schemes like the url rule.
+func isURI(s string, r *rule) bool {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" {
+		return false
+	}
+	return allowedScheme(u.Scheme, r.values)
+}
+
+func allowedScheme(scheme string, schemes []string) bool {
+	if len(schemes) == 0 {
+		return true
+	}
+	for _, s := range schemes {
+		if strings.EqualFold(scheme, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHostname reports whether s is a host name as specified by RFC 1123:
+// dot-separated labels of letters, digits and hyphens, each at most 63
+// characters long and neither starting nor ending with a hyphen.
+func isHostname(s string, _ *rule) bool {
+	if s == "" || len(s) > 253 {
+		return false
+	}
+	for label := range strings.SplitSeq(s, ".") {
+		if !isLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// isFQDN reports whether s is a fully qualified domain name: a host name
+// with at least two labels whose last label is not numeric. A trailing
+// dot is allowed.
+func isFQDN(s string, _ *rule) bool {
+	s = strings.TrimSuffix(s, ".")
+	if !isHostname(s, nil) {
+		return false
+	}
+	i := strings.LastIndexByte(s, '.')
+	if i < 0 {
+		return false
+	}
+	for _, c := range s[i+1:] {
+		if c < '0' || c > '9' {
+			return true
+		}
+	}
+	return false
+}
+
+func isLabel(label string) bool {
+	if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
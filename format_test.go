@@ -0,0 +1,56 @@
+package govalid_test
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateStringFormats(t *testing.T) {
+	rulesMustMatch(t, []ruleCase{
+		{
+			rules: "email",
+			valid: []string{"gopher@example.com", "a.b+c@sub.example.org"},
+			fail:  []string{"gopher", "gopher@", "Gopher <gopher@example.com>", "a@b@c", strings.Repeat("a", 250) + "@b.co"},
+		},
+		{
+			rules: "url",
+			valid: []string{"https://example.com", "http://localhost:8080/a?b=c#d", "ftp://example.com/file"},
+			fail:  []string{"example.com", "/a/b", "https://", "mailto:gopher@example.com", "http://[::1"},
+		},
+		{
+			rules: "url:https,http",
+			valid: []string{"https://example.com", "HTTP://example.com"},
+			fail:  []string{"ftp://example.com"},
+		},
+		{
+			rules: "uri",
+			valid: []string{"https://example.com", "mailto:gopher@example.com", "urn:isbn:0451450523"},
+			fail:  []string{"example.com", "/a/b", "%zz"},
+		},
+		{
+			rules: "uri:mailto",
+			valid: []string{"mailto:gopher@example.com"},
+			fail:  []string{"https://example.com"},
+		},
+		{
+			rules: "hostname",
+			valid: []string{"localhost", "example.com", "a-b.example.com", "123.example"},
+			fail:  []string{"-a.com", "a-.com", "a..com", "a_b.com", "example.com.", strings.Repeat("a", 64) + ".com"},
+		},
+		{
+			rules: "fqdn",
+			valid: []string{"example.com", "a.b.example.com", "example.com."},
+			fail:  []string{"localhost", "example.123", "a..com", "-a.com"},
+		},
+	})
+	t.Run("fail: dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []string `valid:"dive|email"`
+		}{A: []string{"a@b.co", "b"}}, "field A: index 1: email")
+	})
+	t.Run("ok: not req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"url"`
+		}{})
+	})
+}
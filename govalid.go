@@ -1,21 +1,92 @@
 package govalid
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
+	"net/netip"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
-var customRules = make(map[string]func(v any) error)
+// validation holds the state of a single call to Validate or ValidateAll.
+type validation struct {
+	validateOptions
+	v   *Validator
+	all bool
 
-func Rule(name string, validator func(v any) error) {
-	customRules[name] = validator
+	// sel is the part of the paths given to Fields or Except that applies
+	// to the value being validated.
+	sel selection
 }
 
-func Validate(v any) error {
+// validateOptions holds the settings made by ValidateOption.
+type validateOptions struct {
+	ctx    context.Context
+	groups []string
+	paths  []string
+	except bool
+}
+
+// ValidateOption configures a single call to Validate or ValidateAll.
+type ValidateOption func(*validateOptions)
+
+// WithContext sets the context passed to the ValidateStructContext method
+// of structs implementing ContextStructValidator.
+func WithContext(ctx context.Context) ValidateOption {
+	return func(o *validateOptions) {
+		o.ctx = ctx
+	}
+}
+
+// Groups selects the groups of rules to apply in addition to the rules
+// that apply to every group. The rules of group "create" are read from
+// tags like `valid.create:"req"`.
+func Groups(names ...string) ValidateOption {
+	return func(o *validateOptions) {
+		o.groups = names
+	}
+}
+
+// Fields restricts validation to the fields at paths, like "Name",
+// "Address.City", "Items[0].SKU" or "Items[*].SKU". Fields containing
+// them are only traversed; their own rules are not checked.
+func Fields(paths ...string) ValidateOption {
+	return func(o *validateOptions) {
+		o.paths = paths
+		o.except = false
+	}
+}
+
+// Except skips the fields at paths, written as for Fields, and validates
+// everything else.
+func Except(paths ...string) ValidateOption {
+	return func(o *validateOptions) {
+		o.paths = paths
+		o.except = true
+	}
+}
+
+func newValidation(v *Validator, all bool, opts []ValidateOption) validation {
+	vd := validation{v: v, all: all}
+	if len(opts) > 0 {
+		o := &validateOptions{}
+		for _, opt := range opts {
+			opt(o)
+		}
+		vd.validateOptions = *o
+	}
+	if vd.ctx == nil {
+		vd.ctx = context.Background()
+	}
+	return vd
+}
+
+func (vd *validation) run(v any) error {
 	rv := reflect.ValueOf(v)
 	for rv.Kind() == reflect.Pointer {
 		rv = rv.Elem()
@@ -26,363 +97,532 @@ func Validate(v any) error {
 	if rv.Kind() != reflect.Struct {
 		return fmt.Errorf("can not validate value of kind %s", rv.Kind())
 	}
-	return validateStruct(rv, nil)
+	if vd.paths != nil {
+		root, err := newSelector(rv.Type(), vd.paths)
+		if err != nil {
+			return err
+		}
+		vd.sel = selection{node: root, partial: !vd.except}
+	}
+	return vd.validateStruct(rv, nil)
+}
+
+// collect adds err to errs when the validation is collecting all failures
+// and err is a validation failure. Any other error is returned as is.
+func (vd *validation) collect(errs ValidationErrors, err error) (ValidationErrors, error) {
+	if !vd.all {
+		return errs, err
+	}
+	switch e := err.(type) {
+	case ValidationErrors:
+		return append(errs, e...), nil
+	case *validationError:
+		return append(errs, e), nil
+	}
+	return errs, err
 }
 
-func validate(v reflect.Value, rules []string) error {
+func (vd *validation) validate(v reflect.Value, rules []*rule) error {
+	if vd.sel.partial {
+		rules = traversal(rules)
+	}
 	switch v.Kind() {
 	case reflect.Float32, reflect.Float64:
 		return validateFloat(v.Float(), rules)
 	case reflect.String:
 		return validateString(v.String(), rules)
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Type() == durationType {
+			return validateDuration(time.Duration(v.Int()), rules)
+		}
 		return validateInt(v.Int(), rules)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return validateUint(v.Uint(), rules)
 	case reflect.Struct:
-		return validateStruct(v, rules)
+		switch v.Type() {
+		case timeType:
+			return validateTime(v.Interface().(time.Time), rules)
+		case addrType:
+			return validateAddr(v.Interface().(netip.Addr), rules)
+		case prefixType:
+			return validatePrefix(v.Interface().(netip.Prefix), rules)
+		}
+		return vd.validateStruct(v, rules)
 	case reflect.Pointer:
-		return validatePointer(v, rules)
+		return vd.validatePointer(v, rules)
 	case reflect.Slice, reflect.Array:
-		return validateSlice(v, rules)
+		return vd.validateSlice(v, rules)
+	case reflect.Map:
+		return vd.validateMap(v, rules)
+	case reflect.Bool:
+		return validateBool(v.Bool(), rules)
+	case reflect.Interface:
+		if v.IsNil() {
+			return nilErr(v, rules)
+		}
+		return vd.validate(v.Elem(), rules)
 	}
 	return nil
 }
 
-func validateStruct(rv reflect.Value, rules []string) error {
+func (vd *validation) validateStruct(rv reflect.Value, rules []*rule) error {
 	for _, rule := range rules {
 		if err := customRule(rv.Interface(), rule); err != nil {
 			return err
 		}
 	}
-	ty := rv.Type()
-	for i := range ty.NumField() {
-		sf := ty.Field(i)
-		if !sf.IsExported() {
+	p, err := vd.v.plan(rv.Type())
+	if err != nil {
+		return err
+	}
+	var errs ValidationErrors
+	for _, f := range p.fields {
+		if f.group != "" && !slices.Contains(vd.groups, f.group) {
 			continue
 		}
-		st := sf.Tag
-		tag, ok := st.Lookup("valid")
-		if !ok {
+		sel := vd.sel
+		if vd.enter(fieldSegment(f.name)) {
 			continue
 		}
-		fv := rv.Field(i)
-		parts := strings.Split(tag, "|")
-		if err := validate(fv, parts); err != nil {
-			return wrap(fmt.Sprintf("field %s", sf.Name), err)
+		err := vd.validateField(rv, &f)
+		vd.sel = sel
+		if err != nil {
+			var cerr error
+			if errs, cerr = vd.collect(errs, wrap(fieldSegment(f.name), err)); cerr != nil {
+				return cerr
+			}
+		}
+	}
+	if p.hook != noHook && !vd.sel.partial {
+		if err := vd.callHook(rv, p.hook); err != nil {
+			var cerr error
+			if errs, cerr = vd.collect(errs, err); cerr != nil {
+				return cerr
+			}
 		}
 	}
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
-func validatePointer(v reflect.Value, rules []string) error {
-	req := isReq(rules)
-	if req && v.IsNil() {
-		return NewValidationError("required")
+// validateField validates field f of the struct rv.
+func (vd *validation) validateField(rv reflect.Value, f *fieldPlan) error {
+	fv := rv.Field(f.index)
+	if vd.sel.partial {
+		return vd.validate(fv, f.rules)
+	}
+	if !f.req && len(f.conds) > 0 && fv.IsZero() {
+		if rule := requiredBy(rv, f.conds); rule != nil {
+			return newRuleError(rule.name, rule.param, fv.Interface(), "required")
+		}
 	}
-	if !req && v.IsNil() {
+	if err := vd.validate(fv, f.rules); err != nil {
+		return err
+	}
+	if len(f.cross) == 0 || (!f.req && !f.always && fv.IsZero()) {
 		return nil
 	}
+	return validateCrossField(rv, fv, f.cross)
+}
+
+func (vd *validation) validatePointer(v reflect.Value, rules []*rule) error {
+	if v.IsNil() {
+		if err := nilErr(v, rules); err != nil || !hasRule(rules, "always") {
+			return err
+		}
+	}
 	for i, rule := range rules {
-		if rule == "dive" {
-			if !v.IsZero() && i < len(rules) {
-				return validate(v.Elem(), rules[i+1:])
-			}
-			return nil
+		if rule.name == "dive" {
+			return vd.validate(v.Elem(), rules[i+1:])
 		}
-		if err := customRule(v, rule); err != nil {
+		if err := customRule(v.Interface(), rule); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func validateSlice(v reflect.Value, rules []string) error {
-	req := isReq(rules)
-	if req && v.IsNil() {
-		return NewValidationError("required")
-	}
-	if !req && v.IsNil() {
-		return nil
+func (vd *validation) validateSlice(v reflect.Value, rules []*rule) error {
+	if v.IsNil() {
+		if err := nilErr(v, rules); err != nil || !hasRule(rules, "always") {
+			return err
+		}
 	}
 	for i, rule := range rules {
-		if rule == "dive" {
+		if rule.custom != nil {
+			if err := customRule(v.Interface(), rule); err != nil {
+				return err
+			}
+			continue
+		}
+		switch rule.name {
+		case "dive":
+			var errs ValidationErrors
 			if !v.IsZero() {
 				for j := range v.Len() {
-					if err := validate(v.Index(j), rules[i+1:]); err != nil {
-						return wrap(fmt.Sprintf("index %d", j), err)
+					sel := vd.sel
+					if vd.enter(indexSegment(j)) {
+						continue
+					}
+					err := vd.validate(v.Index(j), rules[i+1:])
+					vd.sel = sel
+					if err != nil {
+						var cerr error
+						if errs, cerr = vd.collect(errs, wrap(indexSegment(j), err)); cerr != nil {
+							return cerr
+						}
 					}
 				}
 			}
+			if len(errs) > 0 {
+				return errs
+			}
 			return nil
-		}
-		max, ok, err := getUintSize(rule, "max")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if uint64(v.Len()) > max {
-				return NewValidationError(fmt.Sprintf("max %d", max))
+		case "max":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if uint64(v.Len()) > rule.uint {
+				return newRuleError(rule.name, rule.param, v.Interface(), fmt.Sprintf("max %d", rule.uint))
+			}
+		case "min":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if uint64(v.Len()) < rule.uint {
+				return newRuleError(rule.name, rule.param, v.Interface(), fmt.Sprintf("min %d", rule.uint))
+			}
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if !rule.cmp(cmp.Compare(uint64(v.Len()), rule.uint)) {
+				return newRuleError(rule.name, rule.param, v.Interface(), fmt.Sprintf("%s %d", rule.name, rule.uint))
 			}
-			continue
-		}
-		min, ok, err := getUintSize(rule, "min")
-		if err != nil {
-			return err
 		}
-		if ok {
-			if uint64(v.Len()) < min {
-				return NewValidationError(fmt.Sprintf("min %d", min))
+	}
+	return nil
+}
+
+// validateBool validates a bool, for which req means it must be true.
+// Like req, eq and ne are checked even if the value is false.
+func validateBool(v bool, rules []*rule) error {
+	req := isReq(rules)
+	if req && !v {
+		return newRuleError("req", "", v, "required")
+	}
+	skip := !req && !v && !hasRule(rules, "always")
+	for _, rule := range rules {
+		if rule.custom != nil {
+			if skip {
+				continue
+			}
+			if err := customRule(v, rule); err != nil {
+				return err
 			}
 			continue
 		}
-		if err := customRule(v, rule); err != nil {
-			return err
+		switch rule.name {
+		case "eq", "ne":
+			if rule.boolErr != nil {
+				return rule.boolErr
+			}
+			if (v == rule.bool) != (rule.name == "eq") {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("%s %t", rule.name, rule.bool))
+			}
 		}
 	}
 	return nil
 }
 
-func validateFloat(v float64, rules []string) error {
+func validateFloat(v float64, rules []*rule) error {
 	req := isReq(rules)
 	if req && v == 0 {
-		return NewValidationError("required")
+		return newRuleError("req", "", v, "required")
 	}
-	if !req && v == 0 {
+	if !req && v == 0 && !hasRule(rules, "always") {
 		return nil
 	}
 	for _, rule := range rules {
-		max, ok, err := getFloatSize(rule, "max")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if v > max {
-				return NewValidationError(fmt.Sprintf("max %f", max))
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
 			}
 			continue
 		}
-		min, ok, err := getFloatSize(rule, "min")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if v < min {
-				return NewValidationError(fmt.Sprintf("min %f", min))
+		switch rule.name {
+		case "max":
+			if rule.floatErr != nil {
+				return rule.floatErr
+			}
+			if v > rule.float {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("max %f", rule.float))
+			}
+		case "min":
+			if rule.floatErr != nil {
+				return rule.floatErr
+			}
+			if v < rule.float {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("min %f", rule.float))
+			}
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			if rule.floatErr != nil {
+				return rule.floatErr
+			}
+			if !rule.cmp(cmp.Compare(v, rule.float)) {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("%s %f", rule.name, rule.float))
 			}
-			continue
-		}
-		if err := customRule(v, rule); err != nil {
-			return err
 		}
 	}
 	return nil
 }
 
-func validateInt(v int64, rules []string) error {
+func validateInt(v int64, rules []*rule) error {
 	req := isReq(rules)
 	if req && v == 0 {
-		return NewValidationError("required")
+		return newRuleError("req", "", v, "required")
 	}
-	if !req && v == 0 {
+	if !req && v == 0 && !hasRule(rules, "always") {
 		return nil
 	}
 	for _, rule := range rules {
-		max, ok, err := getIntSize(rule, "max")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if v > max {
-				return NewValidationError(fmt.Sprintf("max %d", max))
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
 			}
 			continue
 		}
-		min, ok, err := getIntSize(rule, "min")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if v < min {
-				return NewValidationError(fmt.Sprintf("min %d", min))
+		switch rule.name {
+		case "max":
+			if rule.intErr != nil {
+				return rule.intErr
 			}
-			continue
-		}
-		if values, ok := getInValues(rule); ok {
-			validValues := make([]string, 0, len(values))
+			if v > rule.int {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("max %d", rule.int))
+			}
+		case "min":
+			if rule.intErr != nil {
+				return rule.intErr
+			}
+			if v < rule.int {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("min %d", rule.int))
+			}
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			if rule.intErr != nil {
+				return rule.intErr
+			}
+			if !rule.cmp(cmp.Compare(v, rule.int)) {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("%s %d", rule.name, rule.int))
+			}
+		case "port":
+			if v < 1 || v > 65535 {
+				return newRuleError(rule.name, rule.param, v, rule.name)
+			}
+		case "in":
 			found := false
-			for _, valStr := range values {
-				validValues = append(validValues, valStr)
+			for _, valStr := range rule.values {
 				if val, err := strconv.ParseInt(valStr, 10, 64); err == nil && v == val {
 					found = true
 					break
 				}
 			}
 			if !found {
-				return NewValidationError(fmt.Sprintf("in %s", strings.Join(validValues, ",")))
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("in %s", strings.Join(rule.values, ",")))
 			}
-			continue
-		}
-		if err := customRule(v, rule); err != nil {
-			return err
 		}
 	}
 	return nil
 }
 
-func validateUint(v uint64, rules []string) error {
+func validateUint(v uint64, rules []*rule) error {
 	req := isReq(rules)
 	if req && v == 0 {
-		return NewValidationError("required")
+		return newRuleError("req", "", v, "required")
 	}
-	if !req && v == 0 {
+	if !req && v == 0 && !hasRule(rules, "always") {
 		return nil
 	}
 	for _, rule := range rules {
-		max, ok, err := getUintSize(rule, "max")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if v > max {
-				return NewValidationError(fmt.Sprintf("max %d", max))
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
 			}
 			continue
 		}
-		min, ok, err := getUintSize(rule, "min")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if v < min {
-				return NewValidationError(fmt.Sprintf("min %d", min))
+		switch rule.name {
+		case "max":
+			if rule.uintErr != nil {
+				return rule.uintErr
 			}
-			continue
-		}
-		if values, ok := getInValues(rule); ok {
-			validValues := make([]string, 0, len(values))
+			if v > rule.uint {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("max %d", rule.uint))
+			}
+		case "min":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if v < rule.uint {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("min %d", rule.uint))
+			}
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if !rule.cmp(cmp.Compare(v, rule.uint)) {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("%s %d", rule.name, rule.uint))
+			}
+		case "port":
+			if v < 1 || v > 65535 {
+				return newRuleError(rule.name, rule.param, v, rule.name)
+			}
+		case "in":
 			found := false
-			for _, valStr := range values {
-				validValues = append(validValues, valStr)
+			for _, valStr := range rule.values {
 				if val, err := strconv.ParseUint(valStr, 10, 64); err == nil && v == val {
 					found = true
 					break
 				}
 			}
 			if !found {
-				return NewValidationError(fmt.Sprintf("in %s", strings.Join(validValues, ",")))
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("in %s", strings.Join(rule.values, ",")))
 			}
-			continue
-		}
-		if err := customRule(v, rule); err != nil {
-			return err
 		}
 	}
 	return nil
 }
 
-func validateString(v string, rules []string) error {
+func validateString(v string, rules []*rule) error {
 	req := isReq(rules)
 	if req && v == "" {
-		return NewValidationError("required")
+		return newRuleError("req", "", v, "required")
 	}
-	if !req && v == "" {
+	if !req && v == "" && !hasRule(rules, "always") {
 		return nil
 	}
 	for _, rule := range rules {
-		max, ok, err := getUintSize(rule, "max")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if uint64(len(v)) > max {
-				return NewValidationError(fmt.Sprintf("max %d", max))
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
 			}
 			continue
 		}
-		min, ok, err := getUintSize(rule, "min")
-		if err != nil {
-			return err
-		}
-		if ok {
-			if uint64(len(v)) < min {
-				return NewValidationError(fmt.Sprintf("min %d", min))
+		switch rule.name {
+		case "max", "runemax", "graphememax":
+			if rule.uintErr != nil {
+				return rule.uintErr
 			}
-			continue
-		}
-		if values, ok := getInValues(rule); ok {
-			if !slices.Contains(values, v) {
-				return NewValidationError(fmt.Sprintf("in %s", strings.Join(values, ",")))
+			if length(v, rule.unit) > rule.uint {
+				return newRuleError(rule.name, rule.param, v, lengthMessage("max", rule))
+			}
+		case "min", "runemin", "graphememin":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if length(v, rule.unit) < rule.uint {
+				return newRuleError(rule.name, rule.param, v, lengthMessage("min", rule))
+			}
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			if !rule.cmp(strings.Compare(v, rule.param)) {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("%s %s", rule.name, rule.param))
+			}
+		case "in":
+			if !slices.Contains(rule.values, v) {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("in %s", strings.Join(rule.values, ",")))
+			}
+		case "regex", "pattern":
+			if !rule.re.MatchString(v) {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("%s %s", rule.name, rule.param))
+			}
+		case "datetime":
+			if _, err := time.Parse(rule.layout, v); err != nil {
+				return newRuleError(rule.name, rule.param, v, ruleMessage(rule))
+			}
+		case "before", "after", "past", "future", "within":
+			layout := rule.layout
+			if layout == "" {
+				layout = time.RFC3339
+			}
+			if t, err := time.Parse(layout, v); err != nil || !inTime(t, rule) {
+				return newRuleError(rule.name, rule.param, v, ruleMessage(rule))
+			}
+		default:
+			if rule.check != nil && !rule.check(v, rule) {
+				return newRuleError(rule.name, rule.param, v, ruleMessage(rule))
 			}
-			continue
-		}
-		if err := customRule(v, rule); err != nil {
-			return err
 		}
 	}
 	return nil
 }
 
-func customRule(v any, rule string) error {
-	if validator, ok := customRules[rule]; ok {
-		if err := validator(v); err != nil {
-			return err
-		}
+// ruleMessage returns the message for a value that failed a rule, like
+// "email" or "url https,http".
+func ruleMessage(rule *rule) string {
+	if rule.param == "" {
+		return rule.name
 	}
-	return nil
+	return rule.name + " " + rule.param
 }
 
-func getIntSize(rule string, ty string) (int64, bool, error) {
-	prefix := fmt.Sprintf("%s:", ty)
-	if after, ok := strings.CutPrefix(rule, prefix); ok {
-		s := after
-		i, err := strconv.ParseInt(s, 10, 64)
-		if err != nil {
-			return 0, false, err
+func customRule(v any, rule *rule) error {
+	if rule.custom != nil {
+		if err := rule.custom(v); err != nil {
+			return annotate(err, rule.name, rule.param, v)
 		}
-		return i, true, nil
 	}
-	return 0, false, nil
+	return nil
 }
 
-func getUintSize(rule string, ty string) (uint64, bool, error) {
-	prefix := fmt.Sprintf("%s:", ty)
-	if after, ok := strings.CutPrefix(rule, prefix); ok {
-		s := after
-		i, err := strconv.ParseUint(s, 10, 64)
-		if err != nil {
-			return 0, false, err
-		}
-		return i, true, nil
-	}
-	return 0, false, nil
+// isReq tells whether rules require the value itself not to be the zero
+// value.
+func isReq(rules []*rule) bool {
+	return hasRule(rules, "req")
 }
 
-func getFloatSize(rule string, ty string) (float64, bool, error) {
-	prefix := fmt.Sprintf("%s:", ty)
-	if after, ok := strings.CutPrefix(rule, prefix); ok {
-		s := after
-		i, err := strconv.ParseFloat(s, 64)
-		if err != nil {
-			return 0, false, err
+// hasRule tells whether rules contain the rule name for the value itself.
+// Rules after dive or between keys and endkeys apply to elements and keys
+// instead.
+func hasRule(rules []*rule, name string) bool {
+	for i := 0; i < len(rules); i++ {
+		switch rules[i].name {
+		case name:
+			return true
+		case "dive":
+			return false
+		case "keys":
+			for rules[i].name != "endkeys" {
+				i++
+			}
 		}
-		return i, true, nil
 	}
-	return 0, false, nil
+	return false
 }
 
-func getInValues(rule string) ([]string, bool) {
-	prefix := "in:"
-	if after, ok := strings.CutPrefix(rule, prefix); ok {
-		values := strings.Split(after, ",")
-		for i := range values {
-			values[i] = strings.TrimSpace(values[i])
+// diveReq tells whether req appears after dive in rules, outside of keys
+// and endkeys.
+func diveReq(rules []*rule) bool {
+	for i, rule := range rules {
+		if rule.name == "dive" {
+			return hasRule(rules[i+1:], "req") || diveReq(rules[i+1:])
 		}
-		return values, true
 	}
-	return nil, false
+	return false
 }
 
-func isReq(rules []string) bool {
-	return slices.Contains(rules, "req")
+// nilErr returns the error for a nil pointer, slice, map or interface if
+// rules contain req or notnil. A nil pointer or slice is also required if
+// req follows dive, since it has no element to apply it to.
+func nilErr(v reflect.Value, rules []*rule) error {
+	name := "req"
+	if !isReq(rules) && !(v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Slice) && diveReq(rules)) {
+		if name = "notnil"; !hasRule(rules, name) {
+			return nil
+		}
+	}
+	var val any
+	if v.IsValid() {
+		val = v.Interface()
+	}
+	return newRuleError(name, "", val, "required")
 }
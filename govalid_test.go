@@ -2,8 +2,10 @@ package govalid_test
 
 import (
 	"errors"
+	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/twharmon/govalid"
@@ -326,6 +328,50 @@ func TestValidateFloat(t *testing.T) {
 	})
 }
 
+func TestValidateRegex(t *testing.T) {
+	govalid.Pattern("slug", regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`))
+	t.Run("fail: regex", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"regex:^[a-z]+$"`
+		}{A: "a1"}, "field A: regex ^[a-z]+$")
+	})
+	t.Run("ok: regex", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"regex:^[a-z]+$"`
+		}{A: "abc"})
+	})
+	t.Run("ok: regex not req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"regex:^[a-z]+$"`
+		}{})
+	})
+	t.Run("fail: regex dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []string `valid:"dive|regex:^\\d{2,3}$"`
+		}{A: []string{"12", "1234"}}, "field A: index 1: regex")
+	})
+	t.Run("fail: pattern", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"req|pattern:slug"`
+		}{A: "Not a slug"}, "field A: pattern slug")
+	})
+	t.Run("ok: pattern", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"req|pattern:slug"`
+		}{A: "a-slug"})
+	})
+	t.Run("illegal: regex", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"regex:(a"`
+		}{A: "a"}, "field A", "regex")
+	})
+	t.Run("illegal: unknown pattern", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"pattern:nope"`
+		}{A: "a"}, "unknown pattern")
+	})
+}
+
 func TestValidateCustomRule(t *testing.T) {
 	alpha := regexp.MustCompile("^[a-zA-Z]+$")
 	govalid.Rule("alpha", func(v any) error {
@@ -355,6 +401,232 @@ func TestValidateCustomRule(t *testing.T) {
 	})
 }
 
+func TestValidateCustomRuleArgument(t *testing.T) {
+	v := govalid.New()
+	var got []any
+	v.Rule("record", func(a any) error {
+		got = append(got, a)
+		return nil
+	})
+	s := "a"
+	if err := v.Validate(struct {
+		A *string  `valid:"record"`
+		B []string `valid:"record"`
+	}{A: &s, B: []string{"b"}}); err != nil {
+		t.Fatalf("expected nil err; got %s", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 calls; got %d", len(got))
+	}
+	if p, ok := got[0].(*string); !ok || p != &s {
+		t.Fatalf("expected *string; got %T", got[0])
+	}
+	if sl, ok := got[1].([]string); !ok || len(sl) != 1 || sl[0] != "b" {
+		t.Fatalf("expected []string; got %T", got[1])
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	type A struct {
+		A string `valid:"req"`
+	}
+	type B struct {
+		B  string  `valid:"req"`
+		C  int     `valid:"min:3"`
+		As []A     `valid:"dive"`
+		P  *string `valid:"dive|min:2"`
+	}
+	val := B{C: 1, As: []A{{A: "a"}, {}, {}}, P: ptr("a")}
+	t.Run("fail: every field", func(t *testing.T) {
+		err := govalid.ValidateAll(val)
+		var verrs govalid.ValidationErrors
+		if !errors.As(err, &verrs) {
+			t.Fatalf("expected validation errors; got %v", err)
+		}
+		if len(verrs) != 5 {
+			t.Fatalf("expected 5 errors; got %d: %s", len(verrs), verrs)
+		}
+		for i, want := range []string{"field B: required", "field C: min 3", "field As: index 1: field A: required", "field As: index 2: field A: required", "field P: min 2"} {
+			if verrs[i].Error() != want {
+				t.Fatalf("expected error %d to be %s; got %s", i, want, verrs[i])
+			}
+		}
+	})
+	t.Run("fail: first only", func(t *testing.T) {
+		err := govalid.Validate(val)
+		if _, ok := err.(govalid.ValidationErrors); ok {
+			t.Fatalf("expected single error; got %s", err)
+		}
+		validationErrMustInclude(t, val, "field B: required")
+	})
+	t.Run("ok", func(t *testing.T) {
+		if err := govalid.ValidateAll(B{B: "b", C: 3}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("errors.Is", func(t *testing.T) {
+		errOdd := govalid.NewValidationError("must be even")
+		govalid.Rule("even", func(v any) error {
+			if v.(int64)%2 != 0 {
+				return errOdd
+			}
+			return nil
+		})
+		err := govalid.ValidateAll(struct {
+			A int `valid:"even"`
+			B int `valid:"even"`
+		}{A: 2, B: 3})
+		if !errors.Is(err, errOdd) {
+			t.Fatalf("expected err to match; got %s", err)
+		}
+		if errors.Is(err, govalid.NewValidationError("must be even")) {
+			t.Fatalf("expected err not to match other error")
+		}
+	})
+	t.Run("illegal: min", func(t *testing.T) {
+		err := govalid.ValidateAll(struct {
+			A string `valid:"req"`
+			B string `valid:"min:foo"`
+		}{B: "b"})
+		if _, ok := err.(govalid.ValidationError); ok || err == nil {
+			t.Fatalf("expected non validation error; got %v", err)
+		}
+	})
+}
+
+func TestValidationErrorPath(t *testing.T) {
+	type Item struct {
+		Name string `valid:"min:3"`
+	}
+	type Order struct {
+		Items []*Item `valid:"dive|dive"`
+	}
+	err := govalid.Validate(Order{Items: []*Item{{Name: "abc"}, {Name: "ab"}}})
+	var verr govalid.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected validation error; got %v", err)
+	}
+	t.Run("path", func(t *testing.T) {
+		want := []govalid.PathSegment{
+			{Kind: govalid.FieldSegment, Field: "Items"},
+			{Kind: govalid.IndexSegment, Index: 1},
+			{Kind: govalid.FieldSegment, Field: "Name"},
+		}
+		if !reflect.DeepEqual(verr.Path(), want) {
+			t.Fatalf("expected path %v; got %v", want, verr.Path())
+		}
+	})
+	t.Run("field", func(t *testing.T) {
+		if verr.Field() != "Items[1].Name" {
+			t.Fatalf("expected field Items[1].Name; got %s", verr.Field())
+		}
+	})
+	t.Run("rule", func(t *testing.T) {
+		if verr.Rule() != "min" || verr.Param() != "3" {
+			t.Fatalf("expected rule min 3; got %s %s", verr.Rule(), verr.Param())
+		}
+	})
+	t.Run("value", func(t *testing.T) {
+		if verr.Value() != "ab" {
+			t.Fatalf("expected value ab; got %v", verr.Value())
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		if verr.Error() != "field Items: index 1: field Name: min 3" {
+			t.Fatalf("unexpected message %s", verr)
+		}
+	})
+	t.Run("custom rule", func(t *testing.T) {
+		govalid.Rule("notfoo", func(v any) error {
+			if v == "foo" {
+				return govalid.NewValidationError("must not be foo")
+			}
+			return nil
+		})
+		err := govalid.Validate(struct {
+			A string `valid:"notfoo"`
+		}{A: "foo"})
+		var verr govalid.ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected validation error; got %v", err)
+		}
+		if verr.Field() != "A" || verr.Rule() != "notfoo" || verr.Value() != "foo" {
+			t.Fatalf("unexpected field %s rule %s value %v", verr.Field(), verr.Rule(), verr.Value())
+		}
+	})
+}
+
+func TestValidateCachedPlan(t *testing.T) {
+	type A struct {
+		A string `valid:"req|late"`
+	}
+	t.Run("ok: concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Go(func() {
+				for range 100 {
+					if err := govalid.Validate(A{A: "a"}); err != nil {
+						t.Errorf("expected nil err; got %s", err)
+					}
+				}
+			})
+		}
+		wg.Wait()
+	})
+	t.Run("fail: rule registered after first use", func(t *testing.T) {
+		govalid.Rule("late", func(v any) error {
+			return govalid.NewValidationError("late rule")
+		})
+		validationErrMustInclude(t, A{A: "a"}, "late rule")
+	})
+}
+
+func TestValidateGroups(t *testing.T) {
+	type User struct {
+		ID       string `valid.create:"max:0" valid.update:"req"`
+		Name     string `valid:"max:5"`
+		Password string `valid:"min:8" valid.create:"req"`
+	}
+	t.Run("fail: create", func(t *testing.T) {
+		err := govalid.Validate(User{ID: "1", Password: "password"}, govalid.Groups("create"))
+		if err == nil || err.Error() != "field ID: max 0" {
+			t.Fatalf("unexpected error %v", err)
+		}
+		err = govalid.Validate(User{}, govalid.Groups("create"))
+		if err == nil || err.Error() != "field Password: required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: create", func(t *testing.T) {
+		if err := govalid.Validate(User{Password: "password"}, govalid.Groups("create")); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: update", func(t *testing.T) {
+		err := govalid.ValidateAll(User{Name: "abcdef", Password: "a"}, govalid.Groups("update"))
+		if err == nil || err.Error() != "field ID: required; field Name: max 5; field Password: min 8" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: update", func(t *testing.T) {
+		if err := govalid.Validate(User{ID: "1"}, govalid.Groups("update")); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("ok: no group", func(t *testing.T) {
+		errMustBeNil(t, User{ID: "1"})
+	})
+	t.Run("fail: no group", func(t *testing.T) {
+		validationErrMustInclude(t, User{Password: "a"}, "field Password: min 8")
+	})
+	t.Run("fail: several groups", func(t *testing.T) {
+		err := govalid.ValidateAll(User{ID: "1"}, govalid.Groups("create", "update"))
+		if err == nil || err.Error() != "field ID: max 0; field Password: required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
 func nonValidationErrMustInclude(t *testing.T, val any, msgs ...string) {
 	t.Helper()
 	err := govalid.Validate(val)
@@ -399,3 +671,53 @@ func errMustBeNil(t *testing.T, val any) {
 func ptr[T any](v T) *T {
 	return &v
 }
+
+// ruleCase lists strings that rules must accept and reject.
+type ruleCase struct {
+	rules string
+	valid []string
+	fail  []string
+}
+
+// rulesMustMatch checks each case both with Var and on a string field
+// tagged with its rules. Rejected strings must fail with a validation
+// error reading like the rules, as "uuid 4" for "uuid:4".
+func rulesMustMatch(t *testing.T, cases []ruleCase) {
+	t.Helper()
+	v := govalid.New()
+	for _, c := range cases {
+		typ := reflect.StructOf([]reflect.StructField{{
+			Name: "A",
+			Type: reflect.TypeFor[string](),
+			Tag:  reflect.StructTag(`valid:"` + c.rules + `"`),
+		}})
+		field := func(s string) any {
+			sv := reflect.New(typ).Elem()
+			sv.Field(0).SetString(s)
+			return sv.Interface()
+		}
+		msg := strings.Replace(c.rules, ":", " ", 1)
+		for _, s := range c.valid {
+			t.Run("ok: "+c.rules+" "+s, func(t *testing.T) {
+				if err := v.Var(s, c.rules); err != nil {
+					t.Fatalf("Var: expected nil err; got %s", err)
+				}
+				if err := v.Validate(field(s)); err != nil {
+					t.Fatalf("Validate: expected nil err; got %s", err)
+				}
+			})
+		}
+		for _, s := range c.fail {
+			t.Run("fail: "+c.rules+" "+s, func(t *testing.T) {
+				err := v.Var(s, c.rules)
+				if _, ok := err.(govalid.ValidationError); !ok || err.Error() != msg {
+					t.Fatalf("Var: expected validation error %s; got %v", msg, err)
+				}
+				err = v.Validate(field(s))
+				if _, ok := err.(govalid.ValidationError); !ok || err.Error() != "field A: "+msg {
+					t.Fatalf("Validate: expected validation error field A: %s; got %v", msg, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package govalid
+
+import (
+	"context"
+	"reflect"
+)
+
+// StructValidator is implemented by types with invariants that span
+// several fields. ValidateStruct is called after the field rules of the
+// struct, wherever the struct is validated. A ValidationError it returns,
+// such as one made by NewFieldError, is reported like a failed rule; any
+// other error is returned as a configuration error.
+type StructValidator interface {
+	ValidateStruct() error
+}
+
+// ContextStructValidator is like StructValidator, but it receives the
+// context given to Validate with WithContext.
+type ContextStructValidator interface {
+	ValidateStructContext(ctx context.Context) error
+}
+
+var (
+	structValidatorType        = reflect.TypeFor[StructValidator]()
+	contextStructValidatorType = reflect.TypeFor[ContextStructValidator]()
+)
+
+// hookKind tells how a struct type implements StructValidator or
+// ContextStructValidator.
+type hookKind int
+
+const (
+	noHook hookKind = iota
+	valueHook
+	pointerHook
+)
+
+func hookOf(t reflect.Type) hookKind {
+	if t.Implements(structValidatorType) || t.Implements(contextStructValidatorType) {
+		return valueHook
+	}
+	pt := reflect.PointerTo(t)
+	if pt.Implements(structValidatorType) || pt.Implements(contextStructValidatorType) {
+		return pointerHook
+	}
+	return noHook
+}
+
+// callHook calls the ValidateStruct or ValidateStructContext method of the
+// struct rv.
+func (vd *validation) callHook(rv reflect.Value, kind hookKind) error {
+	if kind == pointerHook {
+		if rv.CanAddr() {
+			rv = rv.Addr()
+		} else {
+			pv := reflect.New(rv.Type())
+			pv.Elem().Set(rv)
+			rv = pv
+		}
+	}
+	switch h := rv.Interface().(type) {
+	case ContextStructValidator:
+		return h.ValidateStructContext(vd.ctx)
+	case StructValidator:
+		return h.ValidateStruct()
+	}
+	return nil
+}
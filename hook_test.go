@@ -0,0 +1,99 @@
+package govalid_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+type hookPeriod struct {
+	Start int `valid:"req"`
+	End   int `valid:"req"`
+}
+
+func (p hookPeriod) ValidateStruct() error {
+	if p.End < p.Start {
+		return govalid.NewFieldError("End", "must not be before start")
+	}
+	return nil
+}
+
+type hookSchedule struct {
+	Name    string       `valid:"req"`
+	Periods []hookPeriod `valid:"dive"`
+}
+
+func (s *hookSchedule) ValidateStruct() error {
+	if len(s.Periods) > 2 {
+		return govalid.NewValidationError("too many periods")
+	}
+	return nil
+}
+
+type hookCtxKey struct{}
+
+type hookTenant struct {
+	ID string
+}
+
+func (t hookTenant) ValidateStructContext(ctx context.Context) error {
+	if ctx.Value(hookCtxKey{}) != t.ID {
+		return govalid.NewFieldError("ID", "unknown tenant")
+	}
+	return nil
+}
+
+type hookBroken struct{}
+
+func (hookBroken) ValidateStruct() error {
+	return errors.New("broken")
+}
+
+func TestValidateStructHook(t *testing.T) {
+	t.Run("fail: nested by dive", func(t *testing.T) {
+		err := govalid.Validate(hookSchedule{Name: "a", Periods: []hookPeriod{{Start: 1, End: 2}, {Start: 2, End: 1}}})
+		var verr govalid.ValidationError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected validation error; got %v", err)
+		}
+		if verr.Field() != "Periods[1].End" || verr.Error() != "field Periods: index 1: field End: must not be before start" {
+			t.Fatalf("unexpected error %s at %s", verr, verr.Field())
+		}
+	})
+	t.Run("fail: pointer receiver", func(t *testing.T) {
+		p := hookPeriod{Start: 1, End: 1}
+		validationErrMustInclude(t, hookSchedule{Name: "a", Periods: []hookPeriod{p, p, p}}, "too many periods")
+	})
+	t.Run("fail: field rules first", func(t *testing.T) {
+		validationErrMustInclude(t, &hookSchedule{Periods: make([]hookPeriod, 3)}, "field Name: required")
+	})
+	t.Run("fail: all", func(t *testing.T) {
+		p := hookPeriod{Start: 1, End: 1}
+		err := govalid.ValidateAll(&hookSchedule{Periods: []hookPeriod{{Start: 2, End: 1}, p, p}})
+		var verrs govalid.ValidationErrors
+		if !errors.As(err, &verrs) {
+			t.Fatalf("expected validation errors; got %v", err)
+		}
+		if len(verrs) != 3 || verrs[0].Field() != "Name" || verrs[1].Field() != "Periods[0].End" || verrs[2].Error() != "too many periods" {
+			t.Fatalf("unexpected errors %s", verrs)
+		}
+	})
+	t.Run("ok", func(t *testing.T) {
+		errMustBeNil(t, hookSchedule{Name: "a", Periods: []hookPeriod{{Start: 1, End: 2}}})
+	})
+	t.Run("fail: context", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), hookCtxKey{}, "a")
+		err := govalid.Validate(hookTenant{ID: "b"}, govalid.WithContext(ctx))
+		if err == nil || err.Error() != "field ID: unknown tenant" {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if err := govalid.Validate(hookTenant{ID: "a"}, govalid.WithContext(ctx)); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("illegal: other error", func(t *testing.T) {
+		nonValidationErrMustInclude(t, hookBroken{}, "broken")
+	})
+}
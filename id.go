@@ -0,0 +1,133 @@
+package govalid
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+)
+
+func init() {
+	stringRules["uuid"] = isUUID
+	stringRules["ulid"] = isULID
+	stringRules["hexadecimal"] = isHexadecimal
+	stringRules["base64"] = isBase64
+	stringRules["base64url"] = isBase64URL
+	stringRules["jwt"] = isJWT
+	for _, name := range []string{"uuid", "ulid", "hexadecimal", "base64", "base64url", "jwt"} {
+		builtinRules[name] = true
+	}
+}
+
+// isUUID reports whether s is a UUID in its canonical form, like
+// "f47ac10b-58cc-4372-a567-0e02b2c3d479". If the rule has a version, like
+// "uuid:4", s must have that version and the RFC 9562 variant.
+func isUUID(s string, r *rule) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(s[i]) {
+				return false
+			}
+		}
+	}
+	if r.int == 0 {
+		return true
+	}
+	return int64(hexValue(s[14])) == r.int && strings.IndexByte("89abAB", s[19]) >= 0
+}
+
+// isULID reports whether s is a ULID: 26 characters of Crockford's base32
+// whose first character is at most 7, so the timestamp fits in 48 bits.
+func isULID(s string, _ *rule) bool {
+	if len(s) != 26 || s[0] > '7' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !strings.ContainsRune("0123456789ABCDEFGHJKMNPQRSTVWXYZ", rune(upper(s[i]))) {
+			return false
+		}
+	}
+	return true
+}
+
+// isHexadecimal reports whether s is a string of hexadecimal digits with
+// an optional "0x" prefix.
+func isHexadecimal(s string, _ *rule) bool {
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isHex(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isBase64 reports whether s is padded standard base64 as specified by
+// RFC 4648.
+func isBase64(s string, _ *rule) bool {
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
+
+// isBase64URL reports whether s is URL-safe base64, with or without
+// padding.
+func isBase64URL(s string, _ *rule) bool {
+	if strings.HasSuffix(s, "=") {
+		_, err := base64.URLEncoding.DecodeString(s)
+		return err == nil
+	}
+	_, err := base64.RawURLEncoding.DecodeString(s)
+	return err == nil
+}
+
+// isJWT reports whether s has the structure of a JSON Web Token: a header
+// and payload that are base64url-encoded JSON objects and a base64url
+// signature, separated by dots. The signature is not verified.
+func isJWT(s string, _ *rule) bool {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	for _, part := range parts[:2] {
+		b, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil || !bytes.HasPrefix(b, []byte("{")) || !json.Valid(b) {
+			return false
+		}
+	}
+	_, err := base64.RawURLEncoding.DecodeString(parts[2])
+	return err == nil
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	}
+	return c - 'A' + 10
+}
+
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
@@ -0,0 +1,118 @@
+package govalid
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LengthUnit is the unit in which the length of a string is counted.
+type LengthUnit int
+
+const (
+	// Bytes counts the bytes of a string. It is the default.
+	Bytes LengthUnit = iota
+
+	// Runes counts the Unicode code points of a string.
+	Runes
+
+	// Graphemes counts the user-perceived characters of a string, so that
+	// "é" written with a combining accent or a flag emoji counts as one.
+	Graphemes
+)
+
+// Length sets the unit the min and max rules count the length of strings
+// in. The runemin, runemax, graphememin and graphememax rules always
+// count runes or graphemes.
+func Length(unit LengthUnit) Option {
+	return func(v *Validator) {
+		v.unit = unit
+	}
+}
+
+// lengthRules maps the rules limiting the length of strings in a fixed
+// unit to that unit.
+var lengthRules = map[string]LengthUnit{
+	"runemin":     Runes,
+	"runemax":     Runes,
+	"graphememin": Graphemes,
+	"graphememax": Graphemes,
+}
+
+func init() {
+	for name := range lengthRules {
+		builtinRules[name] = true
+	}
+}
+
+// lengthUnit returns the unit the length rule named name counts in.
+func (v *Validator) lengthUnit(name string) LengthUnit {
+	if unit, ok := lengthRules[name]; ok {
+		return unit
+	}
+	return v.unit
+}
+
+// length returns the length of s counted in unit.
+func length(s string, unit LengthUnit) uint64 {
+	switch unit {
+	case Runes:
+		return uint64(utf8.RuneCountInString(s))
+	case Graphemes:
+		return uint64(graphemeCount(s))
+	}
+	return uint64(len(s))
+}
+
+// lengthMessage returns the message for a string that failed the length
+// rule, like "max 20" or "max 20 runes".
+func lengthMessage(limit string, rule *rule) string {
+	switch rule.unit {
+	case Runes:
+		return fmt.Sprintf("%s %d runes", limit, rule.uint)
+	case Graphemes:
+		return fmt.Sprintf("%s %d graphemes", limit, rule.uint)
+	}
+	return fmt.Sprintf("%s %d", limit, rule.uint)
+}
+
+// graphemeCount approximates the number of extended grapheme clusters in s
+// as specified by Unicode Standard Annex #29. Combining marks, joiners,
+// variation selectors, emoji modifiers and tags extend the preceding
+// character, as do Hangul vowel and trailing jamo, the character after a
+// zero width joiner and the second of a pair of regional indicators.
+func graphemeCount(s string) int {
+	n := 0
+	prev := rune(-1)
+	ri := 0
+	for _, c := range s {
+		join := prev == '\r' && c == '\n' || prev == '\u200d' || isGraphemeExtend(c)
+		if 0x1f1e6 <= c && c <= 0x1f1ff {
+			join = join || ri%2 == 1
+			ri++
+		} else {
+			ri = 0
+		}
+		if !join || prev < 0 {
+			n++
+		}
+		prev = c
+	}
+	return n
+}
+
+func isGraphemeExtend(c rune) bool {
+	switch {
+	case unicode.In(c, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case c == '\u200c' || c == '\u200d':
+		return true
+	case 0x1f3fb <= c && c <= 0x1f3ff:
+		return true
+	case 0xe0020 <= c && c <= 0xe007f:
+		return true
+	case 0x1160 <= c && c <= 0x11ff:
+		return true
+	}
+	return false
+}
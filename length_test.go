@@ -0,0 +1,113 @@
+package govalid_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateRuneLength(t *testing.T) {
+	t.Run("ok: runemax", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"runemax:10"`
+		}{A: "日本語のタイトルです"})
+	})
+	t.Run("fail: runemax", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"runemax:3"`
+		}{A: "日本語の"}, "field A: max 3 runes")
+	})
+	t.Run("ok: runemin", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"runemin:3"`
+		}{A: "日本語"})
+	})
+	t.Run("fail: runemin", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"runemin:3"`
+		}{A: "日本"}, "field A: min 3 runes")
+	})
+	t.Run("fail: max counts bytes", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"max:3"`
+		}{A: "日本"}, "field A: max 3")
+	})
+	t.Run("fail: dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []string `valid:"dive|runemax:2"`
+		}{A: []string{"日本", "日本語"}}, "field A: index 1: max 2 runes")
+	})
+}
+
+func TestValidateGraphemeLength(t *testing.T) {
+	tests := []struct {
+		s string
+		n int
+	}{
+		{s: "abc", n: 3},
+		{s: "cafe\u0301", n: 4},
+		{s: "\U0001f1ef\U0001f1f5\U0001f1fa\U0001f1f8", n: 2},
+		{s: "\U0001f44d\U0001f3fd", n: 1},
+		{s: "\U0001f468\u200d\U0001f469\u200d\U0001f467", n: 1},
+		{s: "\u2764\ufe0f!", n: 2},
+		{s: "a\r\nb", n: 3},
+		{s: "\u1100\u1161\u11a8", n: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if err := govalid.Var(tt.s, "graphememax:"+strconv.Itoa(tt.n)); err != nil {
+				t.Fatalf("expected nil err; got %s", err)
+			}
+			err := govalid.Var(tt.s, "graphememax:"+strconv.Itoa(tt.n-1))
+			if err == nil || err.Error() != "max "+strconv.Itoa(tt.n-1)+" graphemes" {
+				t.Fatalf("unexpected err %v", err)
+			}
+			err = govalid.Var(tt.s, "graphememin:"+strconv.Itoa(tt.n+1))
+			if err == nil || err.Error() != "min "+strconv.Itoa(tt.n+1)+" graphemes" {
+				t.Fatalf("unexpected err %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatorLength(t *testing.T) {
+	type post struct {
+		Title string   `valid:"min:2|max:3"`
+		Tags  []string `valid:"max:2"`
+	}
+	t.Run("ok: runes", func(t *testing.T) {
+		v := govalid.New(govalid.Length(govalid.Runes))
+		if err := v.Validate(post{Title: "日本語", Tags: []string{"a", "b"}}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: runes", func(t *testing.T) {
+		v := govalid.New(govalid.Length(govalid.Runes))
+		err := v.Validate(post{Title: "日本語の"})
+		if err == nil || err.Error() != "field Title: max 3 runes" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("fail: slice length is unchanged", func(t *testing.T) {
+		v := govalid.New(govalid.Length(govalid.Runes))
+		err := v.Validate(post{Title: "abc", Tags: []string{"a", "b", "c"}})
+		if err == nil || err.Error() != "field Tags: max 2" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("fail: graphemes", func(t *testing.T) {
+		v := govalid.New(govalid.Length(govalid.Graphemes))
+		err := v.Validate(post{Title: "e\u0301"})
+		if err == nil || err.Error() != "field Title: min 2 graphemes" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("fail: fixed unit rules ignore option", func(t *testing.T) {
+		v := govalid.New(govalid.Length(govalid.Graphemes))
+		err := v.Var("e\u0301", "runemax:1")
+		if err == nil || err.Error() != "max 1 runes" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package govalid
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+func keySegment(k any) PathSegment {
+	return PathSegment{Kind: KeySegment, Key: k}
+}
+
+// validateMap validates a map. Rules between keys and endkeys apply to
+// each key, and rules after dive apply to each value.
+func (vd *validation) validateMap(v reflect.Value, rules []*rule) error {
+	if v.IsNil() {
+		if err := nilErr(v, rules); err != nil || !hasRule(rules, "always") {
+			return err
+		}
+	}
+	var keyRules, valueRules []*rule
+	dive := false
+	for i := 0; i < len(rules); i++ {
+		rule := rules[i]
+		if rule.custom != nil {
+			if err := customRule(v.Interface(), rule); err != nil {
+				return err
+			}
+			continue
+		}
+		switch rule.name {
+		case "keys":
+			end := i + 1
+			for rules[end].name != "endkeys" {
+				end++
+			}
+			keyRules = rules[i+1 : end]
+			i = end
+		case "dive":
+			valueRules = rules[i+1:]
+			dive = true
+			i = len(rules)
+		case "max":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if uint64(v.Len()) > rule.uint {
+				return newRuleError(rule.name, rule.param, v.Interface(), fmt.Sprintf("max %d", rule.uint))
+			}
+		case "min":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if uint64(v.Len()) < rule.uint {
+				return newRuleError(rule.name, rule.param, v.Interface(), fmt.Sprintf("min %d", rule.uint))
+			}
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			if rule.uintErr != nil {
+				return rule.uintErr
+			}
+			if !rule.cmp(cmp.Compare(uint64(v.Len()), rule.uint)) {
+				return newRuleError(rule.name, rule.param, v.Interface(), fmt.Sprintf("%s %d", rule.name, rule.uint))
+			}
+		}
+	}
+	if keyRules == nil && !dive {
+		return nil
+	}
+	var errs ValidationErrors
+	for _, k := range sortedKeys(v) {
+		seg := keySegment(k.Interface())
+		sel := vd.sel
+		if vd.enter(seg) {
+			continue
+		}
+		err := vd.validateMapEntry(v, k, keyRules, valueRules, dive)
+		vd.sel = sel
+		if err != nil {
+			var cerr error
+			if errs, cerr = vd.collect(errs, wrap(seg, err)); cerr != nil {
+				return cerr
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (vd *validation) validateMapEntry(v, k reflect.Value, keyRules, valueRules []*rule, dive bool) error {
+	if keyRules != nil && !vd.sel.partial {
+		if err := vd.validate(k, keyRules); err != nil {
+			return err
+		}
+	}
+	if dive {
+		return vd.validate(v.MapIndex(k), valueRules)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of map v in order, so that failures are
+// reported the same way every time.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	slices.SortFunc(keys, func(a, b reflect.Value) int {
+		if c, err := compareValues(a, b); err == nil {
+			return c
+		}
+		return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+	})
+	return keys
+}
@@ -0,0 +1,97 @@
+package govalid_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateMap(t *testing.T) {
+	t.Run("fail: req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"req"`
+		}{}, "required", "A")
+	})
+	t.Run("ok: req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A map[string]int `valid:"req"`
+		}{A: map[string]int{}})
+	})
+	t.Run("ok: nil not req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A map[string]int `valid:"keys|min:2|endkeys|dive|req"`
+		}{})
+	})
+	t.Run("fail: req after endkeys", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"keys|min:1|endkeys|req"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: notnil after endkeys", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"keys|min:1|endkeys|notnil"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: required_with after endkeys", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"keys|min:1|endkeys|required_with:B"`
+			B string
+		}{B: "b"}, "field A: required")
+	})
+	t.Run("fail: var req after endkeys", func(t *testing.T) {
+		var m map[string]int
+		err := govalid.Var(m, "keys|min:1|endkeys|req")
+		if err == nil || err.Error() != "required" {
+			t.Fatalf("expected required; got %v", err)
+		}
+	})
+	t.Run("fail: min", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"min:2"`
+		}{A: map[string]int{"a": 1}}, "field A: min 2")
+	})
+	t.Run("fail: max", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"max:1"`
+		}{A: map[string]int{"a": 1, "b": 2}}, "field A: max 1")
+	})
+	t.Run("fail: keys", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"keys|min:2|endkeys|dive|req"`
+		}{A: map[string]int{"ab": 1, "c": 2}}, "field A: key c: min 2")
+	})
+	t.Run("fail: values", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"keys|min:2|endkeys|dive|req"`
+		}{A: map[string]int{"ab": 1, "cd": 0}}, "field A: key cd: required")
+	})
+	t.Run("ok: keys and values", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A map[string]int `valid:"keys|min:2|endkeys|dive|req"`
+		}{A: map[string]int{"ab": 1, "cd": 2}})
+	})
+	type Item struct {
+		Name string `valid:"req"`
+	}
+	t.Run("fail: struct values", func(t *testing.T) {
+		err := govalid.ValidateAll(struct {
+			Items map[int]Item `valid:"dive"`
+		}{Items: map[int]Item{3: {}, 1: {}, 2: {Name: "a"}}})
+		var verrs govalid.ValidationErrors
+		if !errors.As(err, &verrs) || len(verrs) != 2 {
+			t.Fatalf("expected 2 validation errors; got %v", err)
+		}
+		if verrs[0].Field() != "Items[1].Name" || verrs[1].Field() != "Items[3].Name" {
+			t.Fatalf("unexpected fields %s and %s", verrs[0].Field(), verrs[1].Field())
+		}
+		if verrs[0].Path()[1] != (govalid.PathSegment{Kind: govalid.KeySegment, Key: 1}) {
+			t.Fatalf("unexpected path %v", verrs[0].Path())
+		}
+	})
+	t.Run("illegal: keys without endkeys", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A map[string]int `valid:"keys|min:2|dive|req"`
+		}{}, "endkeys")
+	})
+}
@@ -0,0 +1,146 @@
+package govalid
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
+	"strconv"
+)
+
+var (
+	addrType   = reflect.TypeFor[netip.Addr]()
+	prefixType = reflect.TypeFor[netip.Prefix]()
+)
+
+func init() {
+	stringRules["ip"] = isIP
+	stringRules["ipv4"] = isIPv4
+	stringRules["ipv6"] = isIPv6
+	stringRules["cidr"] = isCIDR
+	stringRules["mac"] = isMAC
+	stringRules["hostport"] = isHostPort
+	stringRules["port"] = isPort
+	for _, name := range []string{"ip", "ipv4", "ipv6", "cidr", "mac", "hostport", "port"} {
+		builtinRules[name] = true
+	}
+}
+
+// isIP reports whether s is an IPv4 or IPv6 address, like "192.0.2.1" or
+// "2001:db8::1".
+func isIP(s string, _ *rule) bool {
+	_, err := netip.ParseAddr(s)
+	return err == nil
+}
+
+func isIPv4(s string, _ *rule) bool {
+	a, err := netip.ParseAddr(s)
+	return err == nil && a.Is4()
+}
+
+func isIPv6(s string, _ *rule) bool {
+	a, err := netip.ParseAddr(s)
+	return err == nil && a.Is6()
+}
+
+// isCIDR reports whether s is an IP prefix in CIDR notation, like
+// "192.0.2.0/24".
+func isCIDR(s string, _ *rule) bool {
+	_, err := netip.ParsePrefix(s)
+	return err == nil
+}
+
+// isMAC reports whether s is a hardware address in one of the forms
+// accepted by net.ParseMAC, like "00:00:5e:00:53:01".
+func isMAC(s string, _ *rule) bool {
+	_, err := net.ParseMAC(s)
+	return err == nil
+}
+
+// isHostPort reports whether s is a host name or IP address followed by a
+// port, like "example.com:443" or "[2001:db8::1]:80".
+func isHostPort(s string, _ *rule) bool {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil || !isPort(port, nil) {
+		return false
+	}
+	return isHostname(host, nil) || isIP(host, nil)
+}
+
+// isPort reports whether s is a port number from 1 to 65535.
+func isPort(s string, _ *rule) bool {
+	n, err := strconv.ParseUint(s, 10, 16)
+	return err == nil && n > 0
+}
+
+func validateAddr(v netip.Addr, rules []*rule) error {
+	req := isReq(rules)
+	if req && !v.IsValid() {
+		return newRuleError("req", "", v, "required")
+	}
+	if !req && !v.IsValid() && !hasRule(rules, "always") {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
+			}
+			continue
+		}
+		var ok bool
+		switch rule.name {
+		case "ip":
+			ok = v.IsValid()
+		case "ipv4":
+			ok = v.Is4()
+		case "ipv6":
+			ok = v.Is6()
+		default:
+			if rule.check != nil {
+				return fmt.Errorf("%s: can not be applied to netip.Addr", rule.name)
+			}
+			continue
+		}
+		if !ok {
+			return newRuleError(rule.name, rule.param, v, rule.name)
+		}
+	}
+	return nil
+}
+
+func validatePrefix(v netip.Prefix, rules []*rule) error {
+	req := isReq(rules)
+	if req && !v.IsValid() {
+		return newRuleError("req", "", v, "required")
+	}
+	if !req && !v.IsValid() && !hasRule(rules, "always") {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
+			}
+			continue
+		}
+		var ok bool
+		switch rule.name {
+		case "cidr":
+			ok = v.IsValid()
+		case "ipv4":
+			ok = v.Addr().Is4()
+		case "ipv6":
+			ok = v.Addr().Is6()
+		default:
+			if rule.check != nil {
+				return fmt.Errorf("%s: can not be applied to netip.Prefix", rule.name)
+			}
+			continue
+		}
+		if !ok {
+			return newRuleError(rule.name, rule.param, v, rule.name)
+		}
+	}
+	return nil
+}
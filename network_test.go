@@ -0,0 +1,183 @@
+package govalid_test
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateNetworkStrings(t *testing.T) {
+	rulesMustMatch(t, []ruleCase{
+		{
+			rules: "ip",
+			valid: []string{"192.0.2.1", "2001:db8::1", "::ffff:192.0.2.1", "fe80::1%eth0"},
+			fail:  []string{"192.0.2", "192.0.2.256", "2001:db8:::1", "example.com"},
+		},
+		{
+			rules: "ipv4",
+			valid: []string{"192.0.2.1", "0.0.0.0"},
+			fail:  []string{"2001:db8::1", "::ffff:192.0.2.1", "192.0.2.1/24"},
+		},
+		{
+			rules: "ipv6",
+			valid: []string{"2001:db8::1", "::1", "::ffff:192.0.2.1"},
+			fail:  []string{"192.0.2.1", "2001:db8::1/64"},
+		},
+		{
+			rules: "cidr",
+			valid: []string{"192.0.2.0/24", "2001:db8::/32", "10.0.0.1/8"},
+			fail:  []string{"192.0.2.0", "192.0.2.0/33", "2001:db8::/129"},
+		},
+		{
+			rules: "mac",
+			valid: []string{"00:00:5e:00:53:01", "00-00-5E-00-53-01", "0000.5e00.5301"},
+			fail:  []string{"00:00:5e:00:53", "00:00:5e:00:53:zz"},
+		},
+		{
+			rules: "hostport",
+			valid: []string{"example.com:443", "localhost:8080", "192.0.2.1:80", "[2001:db8::1]:80"},
+			fail:  []string{"example.com", ":80", "example.com:0", "example.com:65536", "example.com:http", "2001:db8::1:80"},
+		},
+		{
+			rules: "port",
+			valid: []string{"1", "443", "65535"},
+			fail:  []string{"0", "65536", "-1", "http"},
+		},
+	})
+}
+
+func TestValidatePort(t *testing.T) {
+	t.Run("ok: int", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A int    `valid:"port"`
+			B uint16 `valid:"port"`
+		}{A: 8080, B: 65535})
+	})
+	t.Run("fail: int too big", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A int `valid:"port"`
+		}{A: 65536}, "field A: port")
+	})
+	t.Run("fail: int negative", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A int `valid:"port"`
+		}{A: -1}, "field A: port")
+	})
+	t.Run("fail: uint zero always", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A uint16 `valid:"always|port"`
+		}{}, "field A: port")
+	})
+	t.Run("fail: var uint zero always", func(t *testing.T) {
+		if err := govalid.Var(uint16(0), "always|port"); err == nil || err.Error() != "port" {
+			t.Fatalf("expected port err; got %v", err)
+		}
+	})
+	t.Run("fail: uint req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A uint `valid:"req|port"`
+		}{}, "field A: required")
+	})
+}
+
+func TestValidateNetipTypes(t *testing.T) {
+	t.Run("ok: addr", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A netip.Addr `valid:"req|ip"`
+			B netip.Addr `valid:"ipv4"`
+			C netip.Addr `valid:"ipv6"`
+		}{A: netip.MustParseAddr("192.0.2.1"), B: netip.MustParseAddr("192.0.2.1"), C: netip.MustParseAddr("::1")})
+	})
+	t.Run("ok: zero addr not req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A netip.Addr `valid:"ipv4"`
+		}{})
+	})
+	t.Run("fail: zero addr req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A netip.Addr `valid:"req"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: addr ipv4", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A netip.Addr `valid:"ipv4"`
+		}{A: netip.MustParseAddr("2001:db8::1")}, "field A: ipv4")
+	})
+	t.Run("fail: addr ipv6", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A netip.Addr `valid:"ipv6"`
+		}{A: netip.MustParseAddr("192.0.2.1")}, "field A: ipv6")
+	})
+	t.Run("ok: prefix", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A netip.Prefix `valid:"req|cidr|ipv4"`
+		}{A: netip.MustParsePrefix("192.0.2.0/24")})
+	})
+	t.Run("fail: zero prefix req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A netip.Prefix `valid:"req|cidr"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: prefix ipv6", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A netip.Prefix `valid:"ipv6"`
+		}{A: netip.MustParsePrefix("192.0.2.0/24")}, "field A: ipv6")
+	})
+	t.Run("fail: dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []netip.Addr `valid:"dive|req|ipv4"`
+		}{A: []netip.Addr{netip.MustParseAddr("192.0.2.1"), {}}}, "field A: index 1: required")
+	})
+	t.Run("fail: custom rule gets addr", func(t *testing.T) {
+		v := govalid.New()
+		v.Rule("loopback", func(v any) error {
+			if !v.(netip.Addr).IsLoopback() {
+				return govalid.NewValidationError("not loopback")
+			}
+			return nil
+		})
+		err := v.Validate(struct {
+			A netip.Addr `valid:"loopback"`
+		}{A: netip.MustParseAddr("192.0.2.1")})
+		if err == nil || err.Error() != "field A: not loopback" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("ok: addr ip and prefix cidr", func(t *testing.T) {
+		if err := govalid.Var(netip.MustParseAddr("2001:db8::1"), "ip"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+		if err := govalid.Var(netip.MustParsePrefix("2001:db8::/32"), "cidr|ipv6"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: zero addr always ip", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A netip.Addr `valid:"always|ip"`
+		}{}, "field A: ip")
+	})
+	t.Run("illegal: string rules on addr", func(t *testing.T) {
+		for _, rules := range []string{"mac", "hostport", "port", "cidr", "email"} {
+			err := govalid.Var(netip.MustParseAddr("192.0.2.1"), rules)
+			if err == nil || err.Error() != rules+": can not be applied to netip.Addr" {
+				t.Fatalf("unexpected err %v", err)
+			}
+		}
+	})
+	t.Run("illegal: string rules on prefix", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A netip.Prefix `valid:"ip"`
+		}{A: netip.MustParsePrefix("192.0.2.0/24")}, "field A: ip: can not be applied to netip.Prefix")
+	})
+	t.Run("ok: check", func(t *testing.T) {
+		if err := govalid.Check(reflect.TypeFor[struct {
+			A netip.Addr   `valid:"ipv4"`
+			B netip.Prefix `valid:"cidr"`
+			C string       `valid:"hostport"`
+		}]()); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
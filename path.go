@@ -0,0 +1,208 @@
+package govalid
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parsePath parses a path like "Items[3].Name" or "Labels[env]" into its
+// segments. A bracketed number is an index, anything else a map key.
+func parsePath(path string) ([]PathSegment, error) {
+	var segs []PathSegment
+	rest := path
+	for rest != "" {
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: missing ]", path)
+			}
+			inner := rest[1:end]
+			if i, err := strconv.Atoi(inner); err == nil {
+				segs = append(segs, indexSegment(i))
+			} else {
+				segs = append(segs, PathSegment{Kind: KeySegment, Key: inner})
+			}
+			rest = rest[end+1:]
+			if strings.HasPrefix(rest, ".") {
+				rest = rest[1:]
+				if rest == "" {
+					return nil, fmt.Errorf("invalid path %q: empty field name", path)
+				}
+			}
+			continue
+		}
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("invalid path %q: empty field name", path)
+		}
+		segs = append(segs, fieldSegment(rest[:end]))
+		rest = rest[end:]
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("invalid path %q: empty field name", path)
+			}
+		}
+	}
+	return segs, nil
+}
+
+// selector is a tree of the paths given to ValidateFields or
+// ValidateExcept. Each node is a segment of a path, and leaf nodes end a
+// path.
+type selector struct {
+	children map[string]*selector
+	leaf     bool
+}
+
+// selectorKey returns the key of seg in a selector. Indexes and map keys
+// share the form "[k]" so that "[3]" matches both.
+func selectorKey(seg PathSegment) string {
+	switch seg.Kind {
+	case IndexSegment:
+		return fmt.Sprintf("[%d]", seg.Index)
+	case KeySegment:
+		return fmt.Sprintf("[%v]", seg.Key)
+	}
+	return seg.Field
+}
+
+// newSelector builds a selector from paths like "Items[*].Name", checking
+// them against struct type t.
+func newSelector(t reflect.Type, paths []string) (*selector, error) {
+	root := &selector{}
+	for _, path := range paths {
+		segs, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPath(t, path, segs); err != nil {
+			return nil, err
+		}
+		n := root
+		for _, seg := range segs {
+			key := selectorKey(seg)
+			child, ok := n.children[key]
+			if !ok {
+				child = &selector{}
+				if n.children == nil {
+					n.children = make(map[string]*selector)
+				}
+				n.children[key] = child
+			}
+			n = child
+		}
+		n.leaf = true
+	}
+	return root, nil
+}
+
+// checkPath reports an error if segs do not lead anywhere in type t.
+func checkPath(t reflect.Type, path string, segs []PathSegment) error {
+	for _, seg := range segs {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch seg.Kind {
+		case FieldSegment:
+			if t.Kind() != reflect.Struct {
+				return fmt.Errorf("invalid path %q: no field %s", path, seg.Field)
+			}
+			sf, ok := t.FieldByName(seg.Field)
+			if !ok || !sf.IsExported() {
+				return fmt.Errorf("invalid path %q: no field %s", path, seg.Field)
+			}
+			t = sf.Type
+		default:
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Array && t.Kind() != reflect.Map {
+				return fmt.Errorf("invalid path %q: %s is not a slice, array or map", path, t)
+			}
+			t = t.Elem()
+		}
+	}
+	return nil
+}
+
+// child returns the node for seg below n, combining the nodes of an exact
+// index or key and of the wildcard "[*]".
+func (n *selector) child(seg PathSegment) *selector {
+	exact := n.children[selectorKey(seg)]
+	if seg.Kind == FieldSegment {
+		return exact
+	}
+	return mergeSelectors(exact, n.children["[*]"])
+}
+
+func mergeSelectors(a, b *selector) *selector {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	m := &selector{leaf: a.leaf || b.leaf, children: make(map[string]*selector)}
+	for k, c := range a.children {
+		m.children[k] = mergeSelectors(c, b.children[k])
+	}
+	for k, c := range b.children {
+		if _, ok := m.children[k]; !ok {
+			m.children[k] = c
+		}
+	}
+	return m
+}
+
+// selection is the part of a selector that applies to the value being
+// validated. If partial is set, only the rules needed to reach selected
+// values inside the value apply.
+type selection struct {
+	node    *selector
+	partial bool
+}
+
+// enter moves the selection to seg, a field, element or key of the value
+// being validated. It reports whether seg is skipped entirely. The caller
+// restores the previous selection when done with seg.
+func (vd *validation) enter(seg PathSegment) bool {
+	n := vd.sel.node
+	if n == nil {
+		return false
+	}
+	child := n.child(seg)
+	if vd.except {
+		switch {
+		case child == nil:
+			vd.sel = selection{}
+		case child.leaf:
+			return true
+		default:
+			vd.sel = selection{node: child}
+		}
+		return false
+	}
+	switch {
+	case child == nil:
+		return true
+	case child.leaf:
+		vd.sel = selection{}
+	default:
+		vd.sel = selection{node: child, partial: true}
+	}
+	return false
+}
+
+// traversal returns the rules from the first dive on, which are the only
+// ones needed to reach the values inside a partially selected value.
+func traversal(rules []*rule) []*rule {
+	for i, rule := range rules {
+		if rule.name == "dive" {
+			return rules[i:]
+		}
+	}
+	return nil
+}
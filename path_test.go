@@ -0,0 +1,93 @@
+package govalid_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateFields(t *testing.T) {
+	type Item struct {
+		SKU   string `valid:"req|min:3"`
+		Count int    `valid:"req"`
+	}
+	type Address struct {
+		City string `valid:"req"`
+		Zip  string `valid:"req"`
+	}
+	type Order struct {
+		Name    string            `valid:"req"`
+		Address *Address          `valid:"req|dive"`
+		Items   []Item            `valid:"req|min:1|dive"`
+		Labels  map[string]string `valid:"dive|min:2"`
+	}
+	order := Order{
+		Address: &Address{City: "a"},
+		Items:   []Item{{SKU: "abc", Count: 1}, {SKU: "ab"}},
+		Labels:  map[string]string{"env": "p"},
+	}
+	t.Run("ok: unselected fields", func(t *testing.T) {
+		if err := govalid.ValidateFields(order, "Address.City", "Items[0]"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: field", func(t *testing.T) {
+		err := govalid.ValidateFields(order, "Name")
+		if err == nil || err.Error() != "field Name: required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("fail: nested field", func(t *testing.T) {
+		err := govalid.ValidateFields(order, "Address.Zip")
+		if err == nil || err.Error() != "field Address: field Zip: required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("fail: slice element", func(t *testing.T) {
+		err := govalid.ValidateFields(order, "Items[1].SKU")
+		if err == nil || err.Error() != "field Items: index 1: field SKU: min 3" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("fail: every slice element", func(t *testing.T) {
+		err := govalid.ValidateAll(order, govalid.Fields("Items[*].Count"))
+		if err == nil || err.Error() != "field Items: index 1: field Count: required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("fail: map value", func(t *testing.T) {
+		err := govalid.ValidateFields(order, "Labels[env]")
+		if err == nil || err.Error() != "field Labels: key env: min 2" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: nil parent", func(t *testing.T) {
+		if err := govalid.ValidateFields(Order{}, "Address.City"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: except", func(t *testing.T) {
+		err := govalid.ValidateAll(order, govalid.Except("Name", "Items[1]", "Labels"))
+		if err == nil || err.Error() != "field Address: field Zip: required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: except", func(t *testing.T) {
+		if err := govalid.ValidateExcept(order, "Name", "Address.Zip", "Items[*].Count", "Items[1].SKU", "Labels"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("illegal: unknown field", func(t *testing.T) {
+		err := govalid.ValidateFields(order, "Address.Street")
+		var verr govalid.ValidationError
+		if err == nil || errors.As(err, &verr) {
+			t.Fatalf("expected non validation error; got %v", err)
+		}
+	})
+	t.Run("illegal: index on struct", func(t *testing.T) {
+		if err := govalid.ValidateExcept(order, "Address[0]"); err == nil {
+			t.Fatalf("expected non nil err; got nil")
+		}
+	})
+}
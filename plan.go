@@ -0,0 +1,511 @@
+package govalid
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinRules holds the names of the rules govalid implements itself.
+var builtinRules = map[string]bool{
+	"req":  true,
+	"dive": true,
+	"min":  true,
+	"max":  true,
+	"in":   true,
+
+	"notnil": true,
+	"always": true,
+
+	"regex":   true,
+	"pattern": true,
+
+	"keys":    true,
+	"endkeys": true,
+}
+
+func init() {
+	for name := range crossFieldRules {
+		builtinRules[name] = true
+	}
+	for name := range conditionalRules {
+		builtinRules[name] = true
+	}
+}
+
+// rule is a single parsed token of a valid tag, like "min:3".
+type rule struct {
+	text string
+	name string
+	tok  token
+
+	// param is the parameter without quotes and escapes, or as written
+	// if the rule takes a list of values.
+	param string
+
+	// The parameter of min, max and the rules in compareRules, parsed for
+	// each kind of value the rule may be applied to. int also holds the
+	// version of uuid.
+	int      int64
+	intErr   error
+	uint     uint64
+	uintErr  error
+	float    float64
+	floatErr error
+
+	// bool is the parameter of eq and ne for bools.
+	bool    bool
+	boolErr error
+
+	// cmp is the function of a rule in compareRules.
+	cmp func(c int) bool
+
+	// dur is the parameter of min, max and the rules in compareRules for
+	// durations, or of within.
+	// time is the parameter of before and after, and layout is the layout
+	// of datetime or of the datetime rule the rule applies with. now
+	// returns the current time for the rules in timeRules.
+	dur    time.Duration
+	durErr error
+	time   time.Time
+	layout string
+	now    func() time.Time
+
+	// unit is the unit the length of strings is counted in by min, max
+	// and the rules in lengthRules.
+	unit LengthUnit
+
+	// re is the expression of regex or pattern.
+	re *regexp.Regexp
+
+	// check is the function of a rule in stringRules.
+	check func(s string, r *rule) bool
+
+	// values holds the parameter of in, or the values required_if and
+	// required_unless compare fields with.
+	values []string
+
+	// fields holds the other fields a cross-field or conditional rule
+	// refers to.
+	fields []fieldRef
+
+	// custom is the registered rule with the same name, if any.
+	custom func(v any) error
+}
+
+// structPlan is the compiled form of the valid tags of a struct type.
+type structPlan struct {
+	fields []fieldPlan
+	hook   hookKind
+}
+
+type fieldPlan struct {
+	index int
+	name  string
+	rules []*rule
+
+	// group is the group the rules apply to, or empty if they apply to
+	// every group.
+	group string
+
+	// cross and conds hold the cross-field and conditional rules that
+	// apply to the field itself, req tells whether the field is always
+	// required, and always whether its rules apply to the zero value.
+	cross  []*rule
+	conds  []*rule
+	req    bool
+	always bool
+}
+
+type planEntry struct {
+	plan *structPlan
+	err  error
+	gen  uint64
+}
+
+// plan returns the cached plan for struct type t, compiling it on first
+// use or after the rules of v have changed.
+func (v *Validator) plan(t reflect.Type) (*structPlan, error) {
+	if e, ok := v.plans.Load(t); ok {
+		pe := e.(*planEntry)
+		if pe.gen == v.gen.Load() {
+			return pe.plan, pe.err
+		}
+	}
+	v.mu.RLock()
+	gen := v.gen.Load()
+	p, err := v.compileStruct(t, v.strict)
+	v.mu.RUnlock()
+	v.plans.Store(t, &planEntry{plan: p, err: err, gen: gen})
+	return p, err
+}
+
+type varEntry struct {
+	rules []*rule
+	err   error
+	gen   uint64
+}
+
+// varRules returns the cached compiled form of the rules given to Var.
+func (v *Validator) varRules(tag string) ([]*rule, error) {
+	if e, ok := v.vars.load(tag); ok {
+		ve := e.(*varEntry)
+		if ve.gen == v.gen.Load() {
+			return ve.rules, ve.err
+		}
+	}
+	v.mu.RLock()
+	gen := v.gen.Load()
+	rules, err := v.compileRules(tag, v.strict)
+	v.mu.RUnlock()
+	if err == nil {
+		for _, r := range rules {
+			if r.custom == nil && (crossFieldRules[r.name] != nil || conditionalRules[r.name]) {
+				err = &TagError{Tag: tag, Pos: r.tok.pos + 1, Err: fmt.Errorf("%s: can not be used outside a struct", r.name)}
+				break
+			}
+		}
+	}
+	v.vars.store(tag, &varEntry{rules: rules, err: err, gen: gen})
+	return rules, err
+}
+
+// compileStruct compiles the tags of struct type t. Unknown rule names are
+// an error if strict is set. It must be called with v.mu held.
+func (v *Validator) compileStruct(t reflect.Type, strict bool) (*structPlan, error) {
+	p := &structPlan{hook: hookOf(t)}
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if tag, ok := sf.Tag.Lookup(v.tagName()); ok {
+			f, err := v.compileField(t, i, "", tag, strict)
+			if err != nil {
+				return nil, wrap(fieldSegment(sf.Name), err)
+			}
+			p.fields = append(p.fields, f)
+		}
+		for _, gt := range groupTags(sf.Tag, v.tagName()) {
+			f, err := v.compileField(t, i, gt.group, gt.tag, strict)
+			if err != nil {
+				return nil, wrap(fieldSegment(sf.Name), err)
+			}
+			p.fields = append(p.fields, f)
+		}
+	}
+	return p, nil
+}
+
+// compileField compiles the tag of field i of struct type t for group, or
+// for every group if group is empty.
+func (v *Validator) compileField(t reflect.Type, i int, group string, tag string, strict bool) (fieldPlan, error) {
+	rules, err := v.compileRules(tag, strict)
+	if err != nil {
+		return fieldPlan{}, err
+	}
+	f := fieldPlan{index: i, name: t.Field(i).Name, group: group, rules: rules, req: isReq(rules), always: hasRule(rules, "always")}
+	dived, inKeys := false, false
+	for _, r := range rules {
+		switch r.name {
+		case "dive":
+			dived = true
+		case "keys":
+			inKeys = true
+		case "endkeys":
+			inKeys = false
+		}
+		if r.custom != nil || (crossFieldRules[r.name] == nil && !conditionalRules[r.name]) {
+			continue
+		}
+		if dived || inKeys {
+			return fieldPlan{}, &TagError{Tag: tag, Pos: r.tok.pos + 1, Err: fmt.Errorf("%s: can not be used after dive or within keys", r.name)}
+		}
+		if err := resolveFields(t, t.Field(i).Type, tag, r); err != nil {
+			return fieldPlan{}, err
+		}
+		if conditionalRules[r.name] {
+			f.conds = append(f.conds, r)
+		} else {
+			f.cross = append(f.cross, r)
+		}
+	}
+	return f, nil
+}
+
+type groupTag struct {
+	group string
+	tag   string
+}
+
+// groupTags returns the values of the tags named like "valid.create" in
+// st, where name is "valid", in the order they appear.
+func groupTags(st reflect.StructTag, name string) []groupTag {
+	var tags []groupTag
+	// This follows the parsing of reflect.StructTag.Lookup.
+	for st != "" {
+		i := 0
+		for i < len(st) && st[i] == ' ' {
+			i++
+		}
+		st = st[i:]
+		if st == "" {
+			break
+		}
+		i = 0
+		for i < len(st) && st[i] > ' ' && st[i] != ':' && st[i] != '"' && st[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(st) || st[i] != ':' || st[i+1] != '"' {
+			break
+		}
+		key := string(st[:i])
+		st = st[i+1:]
+		i = 1
+		for i < len(st) && st[i] != '"' {
+			if st[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(st) {
+			break
+		}
+		qvalue := string(st[:i+1])
+		st = st[i+1:]
+		group, ok := strings.CutPrefix(key, name+".")
+		if !ok || group == "" {
+			continue
+		}
+		if value, err := strconv.Unquote(qvalue); err == nil {
+			tags = append(tags, groupTag{group: group, tag: value})
+		}
+	}
+	return tags
+}
+
+func (v *Validator) compileRules(tag string, strict bool) ([]*rule, error) {
+	tokens, err := parseTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*rule, len(tokens))
+	keys := -1
+	for i, tok := range tokens {
+		r, err := v.compileRule(tag, tok)
+		if err != nil {
+			return nil, err
+		}
+		if strict && r.custom == nil && r.text != "" && !builtinRules[r.name] {
+			return nil, &TagError{Tag: tag, Pos: tok.pos + 1, Err: fmt.Errorf("unknown rule %q", r.text)}
+		}
+		switch r.name {
+		case "keys":
+			if keys >= 0 {
+				return nil, &TagError{Tag: tag, Pos: keys + 1, Err: errors.New("keys: missing endkeys")}
+			}
+			keys = tok.pos
+		case "endkeys":
+			if keys < 0 {
+				return nil, &TagError{Tag: tag, Pos: tok.pos + 1, Err: errors.New("endkeys: missing keys")}
+			}
+			keys = -1
+		}
+		rules[i] = r
+	}
+	if keys >= 0 {
+		return nil, &TagError{Tag: tag, Pos: keys + 1, Err: errors.New("keys: missing endkeys")}
+	}
+	if err := linkTimeRules(tag, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// listRules holds the built-in rules whose parameter is a list of values.
+var listRules = map[string]bool{
+	"in":               true,
+	"url":              true,
+	"uri":              true,
+	"required_if":      true,
+	"required_unless":  true,
+	"required_with":    true,
+	"required_without": true,
+}
+
+func (v *Validator) compileRule(tag string, tok token) (*rule, error) {
+	r := &rule{text: tok.text, name: tok.name, tok: tok, custom: v.rules[tok.text]}
+	if r.custom != nil {
+		r.param = tok.param
+		return r, nil
+	}
+	paramErr := func(err error) error {
+		if _, ok := err.(*TagError); ok {
+			return err
+		}
+		return &TagError{Tag: tag, Pos: tok.paramPos + 1, Err: fmt.Errorf("%s: %w", r.name, err)}
+	}
+	if build, ok := v.paramRules[r.name]; ok {
+		r.param = tok.param
+		var args []string
+		if tok.param != "" {
+			var err error
+			if args, err = tok.split(tag); err != nil {
+				return nil, err
+			}
+		}
+		custom, err := build(args)
+		if err != nil {
+			return nil, paramErr(err)
+		}
+		r.custom = custom
+		return r, nil
+	}
+	if listRules[r.name] {
+		r.param = tok.param
+	} else {
+		var err error
+		if r.param, err = tok.unquote(tag); err != nil {
+			return nil, err
+		}
+	}
+	switch r.name {
+	case "min", "max", "runemin", "runemax", "graphememin", "graphememax":
+		r.unit = v.lengthUnit(r.name)
+		var err error
+		if r.int, err = strconv.ParseInt(r.param, 10, 64); err != nil {
+			r.intErr = paramErr(fmt.Errorf("invalid integer %q", r.param))
+		}
+		if r.uint, err = strconv.ParseUint(r.param, 10, 64); err != nil {
+			r.uintErr = paramErr(fmt.Errorf("invalid unsigned integer %q", r.param))
+		}
+		if r.float, err = strconv.ParseFloat(r.param, 64); err != nil {
+			r.floatErr = paramErr(fmt.Errorf("invalid number %q", r.param))
+		}
+		if r.dur, err = time.ParseDuration(r.param); err != nil {
+			r.durErr = paramErr(fmt.Errorf("invalid duration %q", r.param))
+		}
+		if r.floatErr != nil && r.durErr != nil {
+			return nil, paramErr(fmt.Errorf("invalid number %q", r.param))
+		}
+	case "in":
+		var err error
+		if r.values, err = tok.split(tag); err != nil {
+			return nil, err
+		}
+	case "url", "uri":
+		if tok.param != "" {
+			var err error
+			if r.values, err = tok.split(tag); err != nil {
+				return nil, err
+			}
+		}
+	case "uuid":
+		if r.param != "" {
+			var err error
+			if r.int, err = strconv.ParseInt(r.param, 10, 64); err != nil || r.int < 1 || r.int > 8 {
+				return nil, paramErr(fmt.Errorf("invalid version %q", r.param))
+			}
+		}
+	case "eq", "ne", "gt", "gte", "lt", "lte":
+		// The parameter may also be compared with strings, so it is only
+		// an error when the rule is applied to a value it does not fit.
+		r.cmp = compareRules[r.name]
+		var err error
+		if r.int, err = strconv.ParseInt(r.param, 10, 64); err != nil {
+			r.intErr = paramErr(fmt.Errorf("invalid integer %q", r.param))
+		}
+		if r.uint, err = strconv.ParseUint(r.param, 10, 64); err != nil {
+			r.uintErr = paramErr(fmt.Errorf("invalid unsigned integer %q", r.param))
+		}
+		if r.float, err = strconv.ParseFloat(r.param, 64); err != nil {
+			r.floatErr = paramErr(fmt.Errorf("invalid number %q", r.param))
+		}
+		if r.dur, err = time.ParseDuration(r.param); err != nil {
+			r.durErr = paramErr(fmt.Errorf("invalid duration %q", r.param))
+		}
+		if r.bool, err = strconv.ParseBool(r.param); err != nil {
+			r.boolErr = paramErr(fmt.Errorf("invalid bool %q", r.param))
+		}
+	case "datetime":
+		if r.layout = layouts[r.param]; r.layout == "" {
+			r.layout = r.param
+		}
+		if r.layout == "" {
+			return nil, paramErr(errors.New("missing layout"))
+		}
+	case "within":
+		var err error
+		if r.dur, err = time.ParseDuration(r.param); err != nil {
+			return nil, paramErr(fmt.Errorf("invalid duration %q", r.param))
+		}
+	case "regex":
+		re, err := compilePattern(r.param)
+		if err != nil {
+			return nil, paramErr(err)
+		}
+		r.re = re
+	case "pattern":
+		re, ok := v.patterns[r.param]
+		if !ok {
+			return nil, paramErr(fmt.Errorf("unknown pattern %q", r.param))
+		}
+		r.re = re
+	}
+	if class, ok := charClasses[r.name]; ok && r.param != "" && (r.param != "unicode" || class.unicode == nil) {
+		return nil, paramErr(fmt.Errorf("invalid variant %q", r.param))
+	}
+	if timeRules[r.name] {
+		r.now = v.clock()
+	}
+	r.check = stringRules[r.name]
+	return r, nil
+}
+
+var patterns cache
+
+// compilePattern compiles the expression of a regex rule, reusing the
+// result for every tag with the same expression while it is cached.
+func compilePattern(expr string) (*regexp.Regexp, error) {
+	if re, ok := patterns.load(expr); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	patterns.store(expr, re)
+	return re, nil
+}
+
+// check compiles the tags of struct type t and of every struct type its
+// tagged fields lead to, reporting unknown rule names.
+func (v *Validator) check(t reflect.Type, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	v.mu.RLock()
+	p, err := v.compileStruct(t, true)
+	v.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	for _, f := range p.fields {
+		ft := t.Field(f.index).Type
+		for ft.Kind() == reflect.Pointer || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array || ft.Kind() == reflect.Map {
+			ft = ft.Elem()
+		}
+		if ft.Kind() != reflect.Struct {
+			continue
+		}
+		if err := v.check(ft, seen); err != nil {
+			return wrap(fieldSegment(f.name), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package govalid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateNotNil(t *testing.T) {
+	t.Run("ok: pointer to zero", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A *int `valid:"notnil|dive|max:10"`
+		}{A: ptr(0)})
+	})
+	t.Run("fail: nil pointer", func(t *testing.T) {
+		err := govalid.Validate(struct {
+			A *int `valid:"notnil"`
+		}{})
+		verr, ok := err.(govalid.ValidationError)
+		if !ok || err.Error() != "field A: required" || verr.Rule() != "notnil" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("fail: req pointer to zero", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A *int `valid:"req|dive|req"`
+		}{A: ptr(0)}, "field A: required")
+	})
+	t.Run("ok: empty slice", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A []string       `valid:"notnil"`
+			B map[string]int `valid:"notnil"`
+		}{A: []string{}, B: map[string]int{}})
+	})
+	t.Run("fail: nil slice", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []string `valid:"notnil"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: nil map", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A map[string]int `valid:"notnil"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: nil slice dive req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []string `valid:"dive|req"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: nil pointer dive req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A *string `valid:"dive|req"`
+		}{}, "field A: required")
+	})
+	t.Run("ok: empty slice dive req", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A []string `valid:"dive|req"`
+		}{A: []string{}})
+	})
+	t.Run("ok: interface holding zero", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A any `valid:"notnil"`
+		}{A: 0})
+	})
+	t.Run("fail: nil interface", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A any `valid:"notnil"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: var nil", func(t *testing.T) {
+		if err := govalid.Var(nil, "notnil"); err == nil || err.Error() != "required" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+}
+
+func TestValidateAlways(t *testing.T) {
+	t.Run("fail: int", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A int `valid:"always|min:1"`
+		}{}, "field A: min 1")
+	})
+	t.Run("ok: int without always", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A int `valid:"min:1"`
+		}{})
+	})
+	t.Run("ok: zero quantity", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A *int `valid:"notnil|dive|always|min:0|max:10"`
+		}{A: ptr(0)})
+	})
+	t.Run("fail: uint", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A uint `valid:"always|in:1,2"`
+		}{}, "field A: in 1,2")
+	})
+	t.Run("fail: float", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A float64 `valid:"always|min:0.5"`
+		}{}, "field A: min 0.500000")
+	})
+	t.Run("fail: string", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"always|min:1"`
+		}{}, "field A: min 1")
+	})
+	t.Run("fail: duration", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A time.Duration `valid:"always|min:1s"`
+		}{}, "field A: min 1s")
+	})
+	t.Run("fail: slice", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []string `valid:"always|min:1"`
+		}{}, "field A: min 1")
+	})
+	t.Run("fail: dive", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A []int `valid:"dive|always|min:1"`
+		}{A: []int{1, 0}}, "field A: index 1: min 1")
+	})
+	t.Run("fail: custom rule", func(t *testing.T) {
+		v := govalid.New()
+		v.Rule("accepted", func(v any) error {
+			if v.(bool) {
+				return nil
+			}
+			return govalid.NewValidationError("must be true")
+		})
+		err := v.Validate(struct {
+			A bool `valid:"always|accepted"`
+		}{})
+		if err == nil || err.Error() != "field A: must be true" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("fail: cross-field", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A int `valid:"always|gtfield:B"`
+			B int
+		}{B: 1}, "field A: gtfield B")
+	})
+	t.Run("fail: var", func(t *testing.T) {
+		if err := govalid.Var(0, "always|min:1"); err == nil || err.Error() != "min 1" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	v := govalid.New()
+	for _, tt := range []struct {
+		rule string
+		msg  string
+	}{
+		{rule: "email", msg: "email"},
+		{rule: "url", msg: "url"},
+		{rule: "uri", msg: "uri"},
+		{rule: "hostname", msg: "hostname"},
+		{rule: "fqdn", msg: "fqdn"},
+		{rule: "ip", msg: "ip"},
+		{rule: "ipv4", msg: "ipv4"},
+		{rule: "ipv6", msg: "ipv6"},
+		{rule: "cidr", msg: "cidr"},
+		{rule: "mac", msg: "mac"},
+		{rule: "hostport", msg: "hostport"},
+		{rule: "port", msg: "port"},
+		{rule: "uuid", msg: "uuid"},
+		{rule: "ulid", msg: "ulid"},
+		{rule: "hexadecimal", msg: "hexadecimal"},
+		{rule: "base64"},
+		{rule: "base64url"},
+		{rule: "jwt", msg: "jwt"},
+		{rule: "alpha"},
+		{rule: "alnum"},
+		{rule: "numeric"},
+		{rule: "ascii"},
+		{rule: "printascii"},
+		{rule: "lower"},
+		{rule: "upper"},
+		{rule: "nospace"},
+		{rule: "slug", msg: "slug"},
+		{rule: "slug:unicode", msg: "slug unicode"},
+	} {
+		t.Run("empty string: "+tt.rule, func(t *testing.T) {
+			err := v.Var("", "always|"+tt.rule)
+			if tt.msg == "" {
+				if err != nil {
+					t.Fatalf("expected nil err; got %s", err)
+				}
+				return
+			}
+			if _, ok := err.(govalid.ValidationError); !ok || err.Error() != tt.msg {
+				t.Fatalf("expected validation error %s; got %v", tt.msg, err)
+			}
+		})
+	}
+	t.Run("fail: empty slug field", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"always|slug"`
+		}{}, "field A: slug")
+	})
+}
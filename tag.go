@@ -0,0 +1,209 @@
+package govalid
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagError reports a malformed rule in a tag.
+type TagError struct {
+	// Tag is the tag, or the rules given to Var.
+	Tag string
+
+	// Pos is the 1-based byte position in Tag where the problem is.
+	Pos int
+
+	Err error
+}
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("tag %q at position %d: %v", e.Tag, e.Pos, e.Err)
+}
+
+func (e *TagError) Unwrap() error {
+	return e.Err
+}
+
+// token is a single rule of a tag as written, like `in:"a,b",c`.
+type token struct {
+	text  string
+	name  string
+	param string
+
+	// pos and paramPos are the 0-based offsets of text and param in the
+	// tag.
+	pos      int
+	paramPos int
+}
+
+// parseTag splits a tag into its rules, which are separated by |.
+//
+// Within the parameter of a rule, a value may be quoted with " or ' where
+// the value begins, that is after the colon or after a comma. A | or ,
+// inside quotes, or escaped with a backslash, does not end the rule or the
+// value. A backslash before any other character is kept, so expressions
+// like `regex:^\d+$` are written as usual.
+func parseTag(tag string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for {
+		tok := token{pos: i}
+		for i < len(tag) && tag[i] != ':' && tag[i] != '|' {
+			i++
+		}
+		tok.name = tag[tok.pos:i]
+		if i < len(tag) && tag[i] == ':' {
+			i++
+			tok.paramPos = i
+			end, err := scanParam(tag, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tok.param = tag[tok.paramPos:i]
+		}
+		tok.text = tag[tok.pos:i]
+		tokens = append(tokens, tok)
+		if i >= len(tag) {
+			return tokens, nil
+		}
+		i++
+	}
+}
+
+// scanParam returns the offset in tag of the end of the parameter that
+// starts at offset i.
+func scanParam(tag string, i int) (int, error) {
+	valueStart := true
+	for i < len(tag) {
+		c := tag[i]
+		switch {
+		case valueStart && c == ' ':
+			i++
+			continue
+		case valueStart && (c == '"' || c == '\''):
+			end, err := scanQuoted(tag, i, len(tag))
+			if err != nil {
+				return 0, err
+			}
+			i = end
+			valueStart = false
+			continue
+		case c == '\\' && i+1 < len(tag):
+			i += 2
+			valueStart = false
+			continue
+		case c == '|':
+			return i, nil
+		}
+		valueStart = c == ','
+		i++
+	}
+	return i, nil
+}
+
+// scanQuoted returns the offset after the quote closing the quoted value
+// that starts at offset i of tag, looking no further than offset end.
+func scanQuoted(tag string, i, end int) (int, error) {
+	q := tag[i]
+	for j := i + 1; j < end; j++ {
+		switch tag[j] {
+		case '\\':
+			j++
+		case q:
+			return j + 1, nil
+		}
+	}
+	return 0, &TagError{Tag: tag, Pos: i + 1, Err: fmt.Errorf("unterminated quote %c", q)}
+}
+
+// unquote returns the parameter of tok as a single value, removing quotes
+// and escapes.
+func (tok token) unquote(tag string) (string, error) {
+	if tok.param != "" && (tok.param[0] == '"' || tok.param[0] == '\'') {
+		end, err := scanQuoted(tag, tok.paramPos, tok.paramPos+len(tok.param))
+		if err != nil {
+			return "", err
+		}
+		if end != tok.paramPos+len(tok.param) {
+			return "", &TagError{Tag: tag, Pos: end + 1, Err: fmt.Errorf("unexpected %q after quoted value", tag[end])}
+		}
+		return unescape(tok.param[1:len(tok.param)-1], tok.param[0]), nil
+	}
+	return unescape(tok.param, 0), nil
+}
+
+// split returns the comma-separated values of the parameter of tok,
+// trimming unquoted values and removing quotes and escapes.
+func (tok token) split(tag string) ([]string, error) {
+	return tok.splitBy(tag, ',')
+}
+
+// splitBy is like split, but splits at sep. Runs of spaces count as one
+// separator if sep is a space.
+func (tok token) splitBy(tag string, sep byte) ([]string, error) {
+	var values []string
+	end := tok.paramPos + len(tok.param)
+	i := tok.paramPos
+	for i <= end {
+		for i < end && tag[i] == ' ' {
+			i++
+		}
+		if sep == ' ' && i == end {
+			break
+		}
+		var value string
+		if i < end && (tag[i] == '"' || tag[i] == '\'') {
+			close, err := scanQuoted(tag, i, end)
+			if err != nil {
+				return nil, err
+			}
+			value = unescape(tag[i+1:close-1], tag[i])
+			i = close
+			for i < end && tag[i] == ' ' && sep != ' ' {
+				i++
+			}
+			if i < end && tag[i] != sep {
+				return nil, &TagError{Tag: tag, Pos: i + 1, Err: fmt.Errorf("unexpected %q after quoted value", tag[i])}
+			}
+		} else {
+			start := i
+			for i < end && tag[i] != sep {
+				if tag[i] == '\\' && i+1 < end {
+					i++
+				}
+				i++
+			}
+			value = unescape(strings.TrimSpace(tag[start:i]), 0)
+		}
+		values = append(values, value)
+		i++
+	}
+	return values, nil
+}
+
+// unescape removes the backslashes before the separators |, , and \, and
+// before quotes, in a value. Within a value quoted with q, only q and \
+// are escaped.
+func unescape(s string, q byte) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			next := s[i+1]
+			escaped := next == '\\' || next == q
+			if q == 0 {
+				escaped = escaped || next == '|' || next == ',' || next == '"' || next == '\''
+			}
+			if escaped {
+				sb.WriteByte(next)
+				i++
+				continue
+			}
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,114 @@
+package govalid_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateTagQuoting(t *testing.T) {
+	type A struct {
+		A string `valid:"in:\"a,b\",c"`
+	}
+	t.Run("ok: quoted comma", func(t *testing.T) {
+		errMustBeNil(t, A{A: "a,b"})
+		errMustBeNil(t, A{A: "c"})
+	})
+	t.Run("fail: quoted comma", func(t *testing.T) {
+		validationErrMustInclude(t, A{A: "a"}, "in")
+	})
+	t.Run("ok: single quoted pipe", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"req|in:'x|y',z|max:3"`
+		}{A: "x|y"})
+	})
+	t.Run("ok: quoted spaces kept", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"in: ' a ' , b"`
+		}{A: " a "})
+	})
+	t.Run("ok: escaped comma", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"in:a\\,b,c"`
+		}{A: "a,b"})
+	})
+	t.Run("ok: escaped pipe", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"in:a\\|b|min:3"`
+		}{A: "a|b"})
+	})
+	t.Run("fail: quoted regex", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A string `valid:"regex:\"^(a|b)$\""`
+		}{A: "c"}, "regex ^(a|b)$")
+	})
+	t.Run("ok: unescaped backslash", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"regex:^\\d+$"`
+		}{A: "123"})
+	})
+	t.Run("ok: quote inside value", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A string `valid:"in:don't,do"`
+		}{A: "don't"})
+	})
+	t.Run("fail: quoted required_if value", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			Status string
+			Reason string `valid:"required_if:Status 'on hold'"`
+		}{Status: "on hold"}, "field Reason: required")
+	})
+}
+
+func TestValidateTagErrors(t *testing.T) {
+	t.Run("illegal: unterminated quote", func(t *testing.T) {
+		tagErrMustBeAt(t, struct {
+			A string `valid:"req|in:\"a,b"`
+		}{A: "a"}, 8, "unterminated quote")
+	})
+	t.Run("illegal: text after quote", func(t *testing.T) {
+		tagErrMustBeAt(t, struct {
+			A string `valid:"in:\"a\"b,c"`
+		}{A: "a"}, 7, "after quoted value")
+	})
+	t.Run("illegal: bad number", func(t *testing.T) {
+		tagErrMustBeAt(t, struct {
+			A string `valid:"req|max:ten"`
+		}{A: "a"}, 9, "invalid number")
+	})
+	t.Run("illegal: fraction on int", func(t *testing.T) {
+		tagErrMustBeAt(t, struct {
+			A int `valid:"req|max:1.5"`
+		}{A: 1}, 9, `max: invalid integer "1.5"`)
+	})
+	t.Run("illegal: negative length", func(t *testing.T) {
+		tagErrMustBeAt(t, struct {
+			A string `valid:"min:-1"`
+		}{A: "a"}, 5, `min: invalid unsigned integer "-1"`)
+	})
+	t.Run("illegal: endkeys", func(t *testing.T) {
+		tagErrMustBeAt(t, struct {
+			A map[string]string `valid:"req|endkeys"`
+		}{}, 5, "missing keys")
+	})
+	t.Run("illegal: var", func(t *testing.T) {
+		err := govalid.Var("a", "in:'a")
+		var terr *govalid.TagError
+		if !errors.As(err, &terr) || terr.Pos != 4 || terr.Tag != "in:'a" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
+func tagErrMustBeAt(t *testing.T, val any, pos int, msg string) {
+	t.Helper()
+	nonValidationErrMustInclude(t, val, msg)
+	var terr *govalid.TagError
+	if !errors.As(govalid.Validate(val), &terr) {
+		t.Fatalf("expected tag error")
+	}
+	if terr.Pos != pos {
+		t.Fatalf("expected position %d; got %d: %s", pos, terr.Pos, terr)
+	}
+}
@@ -0,0 +1,190 @@
+package govalid
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// timeRules holds the rules that compare a time.Time with a fixed time or
+// with the current time.
+var timeRules = map[string]bool{
+	"datetime": true,
+	"before":   true,
+	"after":    true,
+	"past":     true,
+	"future":   true,
+	"within":   true,
+}
+
+// linkTimeRules gives the rules in timeRules applied to strings the layout
+// of the datetime rule before the next dive, keys or endkeys, and parses
+// the parameters of before and after in it.
+func linkTimeRules(tag string, rules []*rule) error {
+	for start := 0; start < len(rules); {
+		end := start
+		layout := ""
+		for ; end < len(rules); end++ {
+			r := rules[end]
+			if r.custom == nil && (r.name == "dive" || r.name == "keys" || r.name == "endkeys") {
+				break
+			}
+			if r.custom == nil && r.name == "datetime" {
+				layout = r.layout
+			}
+		}
+		for _, r := range rules[start:end] {
+			if r.custom != nil || !timeRules[r.name] || r.name == "datetime" {
+				continue
+			}
+			r.layout = layout
+			if r.name == "before" || r.name == "after" {
+				var err error
+				if r.time, err = parseTime(r.param, layout); err != nil {
+					return &TagError{Tag: tag, Pos: r.tok.paramPos + 1, Err: fmt.Errorf("%s: %w", r.name, err)}
+				}
+			}
+		}
+		start = end + 1
+	}
+	return nil
+}
+
+func init() {
+	for name := range timeRules {
+		builtinRules[name] = true
+	}
+}
+
+// Clock sets the function the past, future and within rules get the
+// current time from. The default is time.Now.
+func Clock(now func() time.Time) Option {
+	return func(v *Validator) {
+		v.now = now
+	}
+}
+
+// layouts holds the named layouts of the datetime rule.
+var layouts = map[string]string{
+	"rfc3339": time.RFC3339,
+	"date":    time.DateOnly,
+	"time":    time.TimeOnly,
+}
+
+// parseTime parses the parameter of before and after, which is written in
+// layout, if any, in RFC 3339 format or as a date like "2006-01-02".
+func parseTime(s string, layout string) (time.Time, error) {
+	if layout != "" {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
+
+func validateTime(v time.Time, rules []*rule) error {
+	req := isReq(rules)
+	if req && v.IsZero() {
+		return newRuleError("req", "", v, "required")
+	}
+	if !req && v.IsZero() && !hasRule(rules, "always") {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
+			}
+			continue
+		}
+		if timeRules[rule.name] && !inTime(v, rule) {
+			return newRuleError(rule.name, rule.param, v, ruleMessage(rule))
+		}
+	}
+	return nil
+}
+
+// inTime reports whether t passes the rule in timeRules.
+func inTime(t time.Time, rule *rule) bool {
+	switch rule.name {
+	case "before":
+		return t.Before(rule.time)
+	case "after":
+		return t.After(rule.time)
+	case "past":
+		return t.Before(rule.now())
+	case "future":
+		return t.After(rule.now())
+	case "within":
+		d := t.Sub(rule.now())
+		return -rule.dur <= d && d <= rule.dur
+	}
+	return true
+}
+
+func validateDuration(v time.Duration, rules []*rule) error {
+	req := isReq(rules)
+	if req && v == 0 {
+		return newRuleError("req", "", v, "required")
+	}
+	if !req && v == 0 && !hasRule(rules, "always") {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.custom != nil {
+			if err := customRule(v, rule); err != nil {
+				return err
+			}
+			continue
+		}
+		switch rule.name {
+		case "max":
+			d, err := durationParam(rule)
+			if err != nil {
+				return err
+			}
+			if v > d {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("max %s", rule.param))
+			}
+		case "min":
+			d, err := durationParam(rule)
+			if err != nil {
+				return err
+			}
+			if v < d {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("min %s", rule.param))
+			}
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			d, err := durationParam(rule)
+			if err != nil {
+				return err
+			}
+			if !rule.cmp(cmp.Compare(v, d)) {
+				return newRuleError(rule.name, rule.param, v, fmt.Sprintf("%s %s", rule.name, rule.param))
+			}
+		}
+	}
+	return nil
+}
+
+// durationParam returns the parameter of rule for a time.Duration. An
+// integer without a unit is a number of nanoseconds, as it was before
+// durations were validated apart from other integers.
+func durationParam(rule *rule) (time.Duration, error) {
+	if rule.durErr != nil {
+		if rule.intErr != nil {
+			return 0, rule.durErr
+		}
+		return time.Duration(rule.int), nil
+	}
+	return rule.dur, nil
+}
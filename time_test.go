@@ -0,0 +1,215 @@
+package govalid_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidateTime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	v := govalid.New(govalid.Clock(func() time.Time { return now }))
+	tests := []struct {
+		name string
+		val  any
+		msg  string
+	}{
+		{name: "req", val: struct {
+			A time.Time `valid:"req"`
+		}{}, msg: "field A: required"},
+		{name: "zero not req", val: struct {
+			A time.Time `valid:"past|before:2000-01-01"`
+		}{}},
+		{name: "before", val: struct {
+			A time.Time `valid:"before:2024-01-01"`
+		}{A: time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)}},
+		{name: "not before", val: struct {
+			A time.Time `valid:"before:2024-01-01"`
+		}{A: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}, msg: "field A: before 2024-01-01"},
+		{name: "after", val: struct {
+			A time.Time `valid:"after:2024-01-01T00:00:00+02:00"`
+		}{A: time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC)}},
+		{name: "not after", val: struct {
+			A time.Time `valid:"after:2024-01-01T00:00:00Z"`
+		}{A: time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC)}, msg: "field A: after 2024-01-01T00:00:00Z"},
+		{name: "past", val: struct {
+			A time.Time `valid:"req|past"`
+		}{A: now.Add(-time.Second)}},
+		{name: "not past", val: struct {
+			A time.Time `valid:"req|past"`
+		}{A: now.Add(time.Second)}, msg: "field A: past"},
+		{name: "future", val: struct {
+			A time.Time `valid:"future"`
+		}{A: now.Add(time.Second)}},
+		{name: "not future", val: struct {
+			A time.Time `valid:"future"`
+		}{A: now}, msg: "field A: future"},
+		{name: "within", val: struct {
+			A time.Time `valid:"within:720h"`
+			B time.Time `valid:"within:720h"`
+		}{A: now.Add(-720 * time.Hour), B: now.Add(719 * time.Hour)}},
+		{name: "not within", val: struct {
+			A time.Time `valid:"within:720h"`
+		}{A: now.Add(721 * time.Hour)}, msg: "field A: within 720h"},
+		{name: "pointer", val: struct {
+			A *time.Time `valid:"req|dive|req|future"`
+		}{A: &now}, msg: "field A: future"},
+		{name: "dive", val: struct {
+			A []time.Time `valid:"dive|req"`
+		}{A: []time.Time{now, {}}}, msg: "field A: index 1: required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.val)
+			if tt.msg == "" {
+				if err != nil {
+					t.Fatalf("expected nil err; got %s", err)
+				}
+				return
+			}
+			if _, ok := err.(govalid.ValidationError); !ok || err.Error() != tt.msg {
+				t.Fatalf("expected validation error %s; got %v", tt.msg, err)
+			}
+		})
+	}
+	t.Run("invalid time", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A time.Time `valid:"before:yesterday"`
+		}{}, `field A: tag "before:yesterday" at position 8: before: invalid time "yesterday"`)
+	})
+	t.Run("invalid within", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A time.Time `valid:"within:30d"`
+		}{}, `field A: tag "within:30d" at position 8: within: invalid duration "30d"`)
+	})
+	t.Run("var", func(t *testing.T) {
+		if err := v.Var(now.Add(time.Hour), "future|within:1h"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
+
+func TestValidateDuration(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A time.Duration `valid:"req|min:1m|max:24h"`
+			B time.Duration `valid:"max:1h30m"`
+			C time.Duration `valid:"min:0"`
+		}{A: time.Hour, B: 90 * time.Minute, C: time.Second})
+	})
+	t.Run("fail: req", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A time.Duration `valid:"req"`
+		}{}, "field A: required")
+	})
+	t.Run("fail: max", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A time.Duration `valid:"max:24h"`
+		}{A: 25 * time.Hour}, "field A: max 24h")
+	})
+	t.Run("fail: min", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A time.Duration `valid:"min:1m"`
+		}{A: time.Second}, "field A: min 1m")
+	})
+	t.Run("ok: nanoseconds", func(t *testing.T) {
+		errMustBeNil(t, struct {
+			A time.Duration `valid:"min:1000|max:1000000000"`
+			B time.Duration `valid:"gt:0|lte:1000000000"`
+		}{A: time.Second, B: time.Second})
+	})
+	t.Run("fail: nanoseconds", func(t *testing.T) {
+		validationErrMustInclude(t, struct {
+			A time.Duration `valid:"max:1000000000"`
+		}{A: time.Second + 1}, "field A: max 1000000000")
+	})
+	t.Run("fail: fraction without unit", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A time.Duration `valid:"max:1.5"`
+		}{A: time.Second}, `field A: tag "max:1.5" at position 5: max: invalid duration "1.5"`)
+	})
+	t.Run("fail: duration on float", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A float64 `valid:"min:1s"`
+		}{A: 1}, `field A: tag "min:1s" at position 5: min: invalid number "1s"`)
+		nonValidationErrMustInclude(t, struct {
+			A float64 `valid:"max:24h"`
+		}{A: 1}, `field A: tag "max:24h" at position 5: max: invalid number "24h"`)
+	})
+	t.Run("fail: duration on int", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A int `valid:"max:24h"`
+		}{A: 1}, `field A: tag "max:24h" at position 5: max: invalid integer "24h"`)
+	})
+	t.Run("fail: invalid param", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A time.Duration `valid:"max:soon"`
+		}{}, `field A: tag "max:soon" at position 5: max: invalid number "soon"`)
+	})
+}
+
+func TestValidateDatetime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	v := govalid.New(govalid.Clock(func() time.Time { return now }))
+	tests := []struct {
+		rules string
+		valid []string
+		fail  []string
+		msg   string
+	}{
+		{rules: "datetime:rfc3339", valid: []string{"2024-06-01T12:00:00Z", "2024-06-01T12:00:00.5+02:00"}, fail: []string{"2024-06-01", "2024-06-01 12:00:00", "2024-13-01T12:00:00Z"}, msg: "datetime rfc3339"},
+		{rules: "datetime:date", valid: []string{"2024-02-29"}, fail: []string{"2023-02-29", "2024-6-1", "2024-06-01T00:00:00Z"}, msg: "datetime date"},
+		{rules: "datetime:time", valid: []string{"09:30:00", "23:59:59"}, fail: []string{"24:00:00", "9:30"}, msg: "datetime time"},
+		{rules: `datetime:"02 Jan 06 15:04"`, valid: []string{"01 Jun 24 12:00"}, fail: []string{"2024-06-01"}, msg: "datetime 02 Jan 06 15:04"},
+		{rules: "datetime:date|after:2024-01-01|before:2025-01-01", valid: []string{"2024-06-01"}, fail: []string{"2023-12-31"}, msg: "after 2024-01-01"},
+		{rules: "datetime:date|before:2025-01-01", fail: []string{"2025-01-01"}, msg: "before 2025-01-01"},
+		{rules: "datetime:time|after:09:00:00|before:17:00:00", valid: []string{"12:00:00"}, fail: []string{"08:59:59"}, msg: "after 09:00:00"},
+		{rules: "datetime:date|past", valid: []string{"2024-05-31"}, fail: []string{"2024-06-02"}, msg: "past"},
+		{rules: "future|within:24h", valid: []string{"2024-06-02T11:00:00Z"}, fail: []string{"2024-06-01T11:00:00Z", "2024-06-02"}, msg: "future"},
+		{rules: "within:24h", fail: []string{"2024-06-03T00:00:00Z"}, msg: "within 24h"},
+	}
+	for _, tt := range tests {
+		for _, s := range tt.valid {
+			t.Run("ok: "+tt.rules+" "+s, func(t *testing.T) {
+				if err := v.Var(s, tt.rules); err != nil {
+					t.Fatalf("expected nil err; got %s", err)
+				}
+			})
+		}
+		for _, s := range tt.fail {
+			t.Run("fail: "+tt.rules+" "+s, func(t *testing.T) {
+				err := v.Var(s, tt.rules)
+				if _, ok := err.(govalid.ValidationError); !ok || err.Error() != tt.msg {
+					t.Fatalf("expected validation error %s; got %v", tt.msg, err)
+				}
+			})
+		}
+	}
+	t.Run("fail: dive", func(t *testing.T) {
+		err := v.Validate(struct {
+			A []string `valid:"dive|datetime:date|past"`
+		}{A: []string{"2024-01-01", "2024-07-01"}})
+		if err == nil || err.Error() != "field A: index 1: past" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("fail: map keys", func(t *testing.T) {
+		err := v.Validate(struct {
+			A map[string]string `valid:"keys|datetime:date|endkeys|dive|datetime:time"`
+		}{A: map[string]string{"2024-01-01": "09:00"}})
+		if err == nil || err.Error() != "field A: key 2024-01-01: datetime time" {
+			t.Fatalf("unexpected err %v", err)
+		}
+	})
+	t.Run("fail: missing layout", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"datetime"`
+		}{}, `field A: tag "datetime" at position 1: datetime: missing layout`)
+	})
+	t.Run("fail: before not in layout", func(t *testing.T) {
+		nonValidationErrMustInclude(t, struct {
+			A string `valid:"datetime:time|before:noon"`
+		}{}, `field A: tag "datetime:time|before:noon" at position 22: before: invalid time "noon"`)
+	})
+}
@@ -0,0 +1,236 @@
+package govalid
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Validator validates structs using its own custom rules, options and
+// cache of compiled plans. A Validator is safe for concurrent use, and
+// rules may be registered at any time. The zero value is ready to use and
+// behaves like a Validator returned by New with no options.
+type Validator struct {
+	tag    string
+	strict bool
+	unit   LengthUnit
+	now    func() time.Time
+
+	mu         sync.RWMutex
+	rules      map[string]func(v any) error
+	paramRules map[string]func(args []string) (func(v any) error, error)
+	patterns   map[string]*regexp.Regexp
+
+	// gen is incremented whenever rules change so that plans compiled
+	// with the old rules are not used.
+	gen   atomic.Uint64
+	plans sync.Map
+	vars  cache
+}
+
+// Option configures a Validator.
+type Option func(*Validator)
+
+// TagName sets the struct tag the Validator reads rules from. The default
+// is "valid".
+func TagName(name string) Option {
+	return func(v *Validator) {
+		v.tag = name
+	}
+}
+
+// Strict makes the Validator return an error for any rule in a tag that
+// is neither built in nor registered, instead of ignoring it.
+func Strict() Option {
+	return func(v *Validator) {
+		v.strict = true
+	}
+}
+
+// New returns a Validator with no custom rules.
+func New(opts ...Option) *Validator {
+	v := &Validator{
+		tag:        "valid",
+		rules:      make(map[string]func(v any) error),
+		paramRules: make(map[string]func(args []string) (func(v any) error, error)),
+		patterns:   make(map[string]*regexp.Regexp),
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Rule registers a custom rule that can be used in the tags of any struct
+// validated by v. A rule with the same name replaces the previous one.
+func (v *Validator) Rule(name string, validator func(v any) error) {
+	v.update(func() {
+		v.rules[name] = validator
+		delete(v.paramRules, name)
+	})
+}
+
+// ParamRule registers a custom rule that takes a parameter, like
+// "divisible:7" or "prefix:ab,cd". build is called once for each tag the
+// rule appears in with the comma-separated values after the colon, and
+// returns the function that validates values. An error returned by build
+// is reported as a configuration error, not a validation error.
+func (v *Validator) ParamRule(name string, build func(args []string) (func(v any) error, error)) {
+	v.update(func() {
+		v.paramRules[name] = build
+		delete(v.rules, name)
+	})
+}
+
+// Pattern registers a regular expression that the pattern rule can refer
+// to by name, like "pattern:slug". This avoids writing expressions that
+// contain the tag separators | and , in tags.
+func (v *Validator) Pattern(name string, re *regexp.Regexp) {
+	v.update(func() {
+		v.patterns[name] = re
+	})
+}
+
+// update applies change to the registered rules and discards the plans
+// compiled before it.
+func (v *Validator) update(change func()) {
+	v.mu.Lock()
+	if v.rules == nil {
+		v.rules = make(map[string]func(v any) error)
+		v.paramRules = make(map[string]func(args []string) (func(v any) error, error))
+		v.patterns = make(map[string]*regexp.Regexp)
+	}
+	change()
+	v.gen.Add(1)
+	v.mu.Unlock()
+	v.plans.Clear()
+	v.vars.clear()
+}
+
+// tagName returns the struct tag v reads rules from.
+func (v *Validator) tagName() string {
+	if v.tag == "" {
+		return "valid"
+	}
+	return v.tag
+}
+
+// clock returns the function v gets the current time from.
+func (v *Validator) clock() func() time.Time {
+	if v.now == nil {
+		return time.Now
+	}
+	return v.now
+}
+
+// Validate validates val, which must be a struct or a pointer to a struct,
+// and returns the first failure it finds.
+func (v *Validator) Validate(val any, opts ...ValidateOption) error {
+	vd := newValidation(v, false, opts)
+	return vd.run(val)
+}
+
+// ValidateAll is like Validate, but it checks every field, slice element
+// and pointer target instead of stopping at the first failure. All
+// validation failures are returned together as ValidationErrors.
+func (v *Validator) ValidateAll(val any, opts ...ValidateOption) error {
+	vd := newValidation(v, true, opts)
+	return vd.run(val)
+}
+
+// Check reports any error in the tags of struct type t and of the structs
+// its tagged fields refer to, including rules that are neither built in
+// nor registered. It is meant to be called at startup.
+func (v *Validator) Check(t reflect.Type) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("can not check type of kind %s", t.Kind())
+	}
+	return v.check(t, make(map[reflect.Type]bool))
+}
+
+// Var validates a single value, which need not be a struct, against rules
+// written as in a valid tag, like "req|min:3|in:a,b". Rules that refer to
+// other fields can not be used. The compiled rules are cached, and the
+// cache is emptied when it holds more than a fixed number of rule strings.
+func (v *Validator) Var(val any, rules string) error {
+	compiled, err := v.varRules(rules)
+	if err != nil {
+		return err
+	}
+	vd := newValidation(v, false, nil)
+	rv := reflect.ValueOf(val)
+	if !rv.IsValid() {
+		return nilErr(rv, compiled)
+	}
+	return vd.validate(rv, compiled)
+}
+
+// ValidateFields validates only the fields of val at paths. See Fields.
+func (v *Validator) ValidateFields(val any, paths ...string) error {
+	return v.Validate(val, Fields(paths...))
+}
+
+// ValidateExcept validates all fields of val except those at paths. See
+// Except.
+func (v *Validator) ValidateExcept(val any, paths ...string) error {
+	return v.Validate(val, Except(paths...))
+}
+
+var defaultValidator = New()
+
+// Rule registers a custom rule with the default Validator.
+func Rule(name string, validator func(v any) error) {
+	defaultValidator.Rule(name, validator)
+}
+
+// ValidateFields validates only the fields of v at paths with the default
+// Validator.
+func ValidateFields(v any, paths ...string) error {
+	return defaultValidator.ValidateFields(v, paths...)
+}
+
+// ValidateExcept validates all fields of v except those at paths with the
+// default Validator.
+func ValidateExcept(v any, paths ...string) error {
+	return defaultValidator.ValidateExcept(v, paths...)
+}
+
+// ParamRule registers a custom rule that takes a parameter with the
+// default Validator.
+func ParamRule(name string, build func(args []string) (func(v any) error, error)) {
+	defaultValidator.ParamRule(name, build)
+}
+
+// Pattern registers a named regular expression with the default Validator.
+func Pattern(name string, re *regexp.Regexp) {
+	defaultValidator.Pattern(name, re)
+}
+
+// Validate validates v with the default Validator.
+func Validate(v any, opts ...ValidateOption) error {
+	return defaultValidator.Validate(v, opts...)
+}
+
+// ValidateAll validates v with the default Validator, collecting every
+// failure.
+func ValidateAll(v any, opts ...ValidateOption) error {
+	return defaultValidator.ValidateAll(v, opts...)
+}
+
+// Check checks the tags of struct type t with the default Validator.
+func Check(t reflect.Type) error {
+	return defaultValidator.Check(t)
+}
+
+// Var validates a single value, which need not be a struct, against rules
+// written as in a valid tag, like "req|min:3|in:a,b".
+func Var(v any, rules string) error {
+	return defaultValidator.Var(v, rules)
+}
@@ -0,0 +1,330 @@
+package govalid_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twharmon/govalid"
+)
+
+func TestValidatorRules(t *testing.T) {
+	type A struct {
+		A string `valid:"name"`
+	}
+	v1 := govalid.New()
+	v1.Rule("name", func(v any) error {
+		return govalid.NewValidationError("v1 name")
+	})
+	v2 := govalid.New()
+	v2.Rule("name", func(v any) error {
+		return govalid.NewValidationError("v2 name")
+	})
+	t.Run("fail: v1", func(t *testing.T) {
+		err := v1.Validate(A{A: "a"})
+		if err == nil || err.Error() != "field A: v1 name" {
+			t.Fatalf("expected v1 error; got %v", err)
+		}
+	})
+	t.Run("fail: v2", func(t *testing.T) {
+		err := v2.ValidateAll(A{A: "a"})
+		if err == nil || err.Error() != "field A: v2 name" {
+			t.Fatalf("expected v2 error; got %v", err)
+		}
+	})
+	t.Run("ok: unregistered", func(t *testing.T) {
+		if err := govalid.New().Validate(A{A: "a"}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
+
+func TestValidatorTagName(t *testing.T) {
+	v := govalid.New(govalid.TagName("validate"))
+	type A struct {
+		A string `validate:"req"`
+		B string `valid:"req"`
+	}
+	err := v.Validate(A{B: "b"})
+	if err == nil || err.Error() != "field A: required" {
+		t.Fatalf("expected field A required; got %v", err)
+	}
+	if err := v.Validate(A{A: "a"}); err != nil {
+		t.Fatalf("expected nil err; got %s", err)
+	}
+}
+
+func TestValidatorZeroValue(t *testing.T) {
+	var v govalid.Validator
+	type A struct {
+		A string    `valid:"req|fun"`
+		B time.Time `valid:"past"`
+	}
+	if err := v.Validate(A{}); err == nil || err.Error() != "field A: required" {
+		t.Fatalf("expected field A required; got %v", err)
+	}
+	v.Rule("fun", func(v any) error {
+		return govalid.NewValidationError("not fun")
+	})
+	if err := v.Validate(A{A: "a"}); err == nil || err.Error() != "field A: not fun" {
+		t.Fatalf("expected field A not fun; got %v", err)
+	}
+	if err := v.Var(time.Now().Add(time.Hour), "future"); err != nil {
+		t.Fatalf("expected nil err; got %s", err)
+	}
+}
+
+func TestValidatorConcurrentRule(t *testing.T) {
+	v := govalid.New()
+	type A struct {
+		A string `valid:"req|r0|r1|r2|r3"`
+	}
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Go(func() {
+			v.Rule([]string{"r0", "r1", "r2", "r3"}[i], func(v any) error {
+				return nil
+			})
+		})
+		wg.Go(func() {
+			for range 50 {
+				if err := v.Validate(A{A: "a"}); err != nil {
+					t.Errorf("expected nil err; got %s", err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestValidatorStrict(t *testing.T) {
+	type A struct {
+		A string `valid:"req|emial"`
+	}
+	type B struct {
+		B string `valid:""`
+	}
+	t.Run("ok: not strict", func(t *testing.T) {
+		if err := govalid.New().Validate(A{A: "a"}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("illegal: strict", func(t *testing.T) {
+		err := govalid.New(govalid.Strict()).Validate(A{A: "a"})
+		if _, ok := err.(govalid.ValidationError); ok || err == nil {
+			t.Fatalf("expected non validation error; got %v", err)
+		}
+		if err.Error() != `field A: tag "req|emial" at position 5: unknown rule "emial"` {
+			t.Fatalf("unexpected error %s", err)
+		}
+	})
+	t.Run("ok: strict registered", func(t *testing.T) {
+		v := govalid.New(govalid.Strict())
+		v.Rule("emial", func(v any) error { return nil })
+		if err := v.Validate(A{A: "a"}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("ok: strict empty tag", func(t *testing.T) {
+		if err := govalid.New(govalid.Strict()).Validate(B{}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
+
+func TestCheck(t *testing.T) {
+	type Item struct {
+		Name string `valid:"req|nmae"`
+	}
+	type Order struct {
+		ID    string  `valid:"req|max:10"`
+		Items []*Item `valid:"dive|dive"`
+	}
+	t.Run("illegal: nested unknown rule", func(t *testing.T) {
+		err := govalid.Check(reflect.TypeFor[*Order]())
+		if err == nil || err.Error() != `field Items: field Name: tag "req|nmae" at position 5: unknown rule "nmae"` {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("illegal: bad param", func(t *testing.T) {
+		err := govalid.Check(reflect.TypeFor[struct {
+			A string `valid:"max:ten"`
+		}]())
+		if err == nil || !strings.Contains(err.Error(), "max") {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("illegal: not struct", func(t *testing.T) {
+		if err := govalid.Check(reflect.TypeFor[string]()); err == nil {
+			t.Fatalf("expected non nil err; got nil")
+		}
+	})
+	t.Run("ok", func(t *testing.T) {
+		if err := govalid.Check(reflect.TypeFor[struct {
+			A []string `valid:"req|min:1|dive|in:a,b"`
+		}]()); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+}
+
+func TestValidatorParamRule(t *testing.T) {
+	v := govalid.New()
+	v.ParamRule("divisible", func(args []string) (func(v any) error, error) {
+		if len(args) != 1 {
+			return nil, errors.New("expected one divisor")
+		}
+		d, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil || d == 0 {
+			return nil, fmt.Errorf("invalid divisor %q", args[0])
+		}
+		return func(v any) error {
+			if v.(int64)%d != 0 {
+				return govalid.NewValidationError(fmt.Sprintf("must be divisible by %d", d))
+			}
+			return nil
+		}, nil
+	})
+	v.ParamRule("prefix", func(args []string) (func(v any) error, error) {
+		return func(v any) error {
+			for _, arg := range args {
+				if strings.HasPrefix(v.(string), arg) {
+					return nil
+				}
+			}
+			return govalid.NewValidationError("must start with " + strings.Join(args, " or "))
+		}, nil
+	})
+	t.Run("fail: divisible", func(t *testing.T) {
+		err := v.Validate(struct {
+			A int `valid:"divisible:7"`
+		}{A: 15})
+		verr, ok := err.(govalid.ValidationError)
+		if !ok {
+			t.Fatalf("expected validation error; got %v", err)
+		}
+		if verr.Rule() != "divisible" || verr.Param() != "7" || verr.Error() != "field A: must be divisible by 7" {
+			t.Fatalf("unexpected error %s: %s %s", verr, verr.Rule(), verr.Param())
+		}
+	})
+	t.Run("ok: divisible", func(t *testing.T) {
+		if err := v.Validate(struct {
+			A int `valid:"divisible:7"`
+		}{A: 14}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: prefix", func(t *testing.T) {
+		err := v.Validate(struct {
+			A string `valid:"prefix:ab, cd"`
+		}{A: "ef"})
+		if err == nil || err.Error() != "field A: must start with ab or cd" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: prefix", func(t *testing.T) {
+		if err := v.Validate(struct {
+			A string `valid:"prefix:ab, cd"`
+		}{A: "cdef"}); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("illegal: malformed param", func(t *testing.T) {
+		err := v.Validate(struct {
+			A int `valid:"divisible:x"`
+		}{A: 14})
+		if _, ok := err.(govalid.ValidationError); ok || err == nil {
+			t.Fatalf("expected non validation error; got %v", err)
+		}
+		if err.Error() != `field A: tag "divisible:x" at position 11: divisible: invalid divisor "x"` {
+			t.Fatalf("unexpected error %s", err)
+		}
+	})
+	t.Run("illegal: missing param", func(t *testing.T) {
+		if err := v.Check(reflect.TypeFor[struct {
+			A int `valid:"divisible"`
+		}]()); err == nil {
+			t.Fatalf("expected non nil err; got nil")
+		}
+	})
+}
+
+func TestVar(t *testing.T) {
+	t.Run("fail: req", func(t *testing.T) {
+		err := govalid.Var("", "req|min:3")
+		if _, ok := err.(govalid.ValidationError); !ok || err.Error() != "required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("fail: nil req", func(t *testing.T) {
+		if err := govalid.Var(nil, "req"); err == nil || err.Error() != "required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: nil", func(t *testing.T) {
+		if err := govalid.Var(nil, "min:3"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: in", func(t *testing.T) {
+		err := govalid.Var("c", "req|in:a,b")
+		verr, ok := err.(govalid.ValidationError)
+		if !ok || verr.Rule() != "in" || verr.Field() != "" || verr.Error() != "in a,b" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: in", func(t *testing.T) {
+		if err := govalid.Var("abc", "req|min:3|in:abc,def"); err != nil {
+			t.Fatalf("expected nil err; got %s", err)
+		}
+	})
+	t.Run("fail: int", func(t *testing.T) {
+		if err := govalid.Var(uint8(2), "min:3"); err == nil || err.Error() != "min 3" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("fail: slice", func(t *testing.T) {
+		err := govalid.Var([]string{"abc", "d"}, "min:1|dive|min:2")
+		if err == nil || err.Error() != "index 1: min 2" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("fail: pointer", func(t *testing.T) {
+		if err := govalid.Var(ptr(""), "req|dive|req"); err == nil || err.Error() != "required" {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+	t.Run("ok: many rule strings", func(t *testing.T) {
+		v := govalid.New()
+		for i := range 3000 {
+			if err := v.Var(i, "max:"+strconv.Itoa(i)); err != nil {
+				t.Fatalf("expected nil err; got %s", err)
+			}
+			if err := v.Var(i+1, "max:"+strconv.Itoa(i)); err == nil {
+				t.Fatalf("expected max %d err; got nil", i)
+			}
+		}
+	})
+	t.Run("illegal: bad param", func(t *testing.T) {
+		err := govalid.Var("a", "max:x")
+		if _, ok := err.(govalid.ValidationError); ok || err == nil {
+			t.Fatalf("expected non validation error; got %v", err)
+		}
+	})
+	t.Run("illegal: cross field", func(t *testing.T) {
+		err := govalid.Var("a", "eqfield:B")
+		if _, ok := err.(govalid.ValidationError); ok || err == nil {
+			t.Fatalf("expected non validation error; got %v", err)
+		}
+	})
+	t.Run("illegal: strict", func(t *testing.T) {
+		if err := govalid.New(govalid.Strict()).Var("a", "req|emial"); err == nil {
+			t.Fatalf("expected non nil err; got nil")
+		}
+	})
+}